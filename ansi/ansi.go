@@ -0,0 +1,196 @@
+// Package ansi provides the primitives for dealing with the ANSI escape
+// sequences (ECMA-48) that terminal programs write to their output.
+//
+// It does not interpret the sequences - it only knows where they start
+// and where they end, allowing callers to split, strip or inspect the
+// data of an event without breaking sequences apart.
+package ansi
+
+import (
+	"strings"
+)
+
+const (
+	esc = 0x1b
+	bel = 0x07
+	del = 0x7f
+)
+
+// Kind represents the kind of a token.
+type Kind int
+
+const (
+	// Text is a run of printable characters.
+	Text Kind = iota
+
+	// Control is a single C0 control character (e.g., `\r`, `\n`,
+	// `\b`, `\a`).
+	Control
+
+	// Escape is an escape sequence (e.g., `\x1b[1m`).
+	Escape
+)
+
+// Token is a piece of terminal output.
+type Token struct {
+	// Kind indicates what the data represents.
+	Kind Kind
+
+	// Data is the raw data of the token.
+	Data string
+
+	// Incomplete indicates that the data ended before the escape
+	// sequence could be terminated.
+	//
+	// This is only ever set for the last token of a tokenization.
+	Incomplete bool
+}
+
+// Tokenize splits terminal output into text, control characters and
+// escape sequences.
+//
+// The concatenation of the data of all tokens is always equal to the
+// input.
+func Tokenize(data string) (tokens []Token) {
+	tokens = make([]Token, 0)
+
+	var (
+		start int
+		i     int
+	)
+
+	for i < len(data) {
+		b := data[i]
+
+		switch {
+		case b == esc:
+			end, complete := scanEscape(data, i)
+			tokens = append(tokens, Token{
+				Kind:       Escape,
+				Data:       data[i:end],
+				Incomplete: !complete,
+			})
+			i = end
+		case b < 0x20 || b == del:
+			tokens = append(tokens, Token{
+				Kind: Control,
+				Data: data[i : i+1],
+			})
+			i++
+		default:
+			start = i
+			for i < len(data) && isText(data[i]) {
+				i++
+			}
+			tokens = append(tokens, Token{
+				Kind: Text,
+				Data: data[start:i],
+			})
+		}
+	}
+
+	return
+}
+
+// Strip removes all escape sequences from the data, keeping only
+// text and control characters.
+func Strip(data string) string {
+	var builder strings.Builder
+
+	for _, token := range Tokenize(data) {
+		if token.Kind == Escape {
+			continue
+		}
+
+		builder.WriteString(token.Data)
+	}
+
+	return builder.String()
+}
+
+func isText(b byte) bool {
+	return b >= 0x20 && b != del
+}
+
+// scanEscape finds the end of the escape sequence that starts at
+// `start`, indicating whether it could be terminated or not.
+func scanEscape(data string, start int) (end int, complete bool) {
+	end = start + 1
+	if end >= len(data) {
+		return
+	}
+
+	switch introducer := data[end]; {
+	case introducer == '[':
+		// control sequence: parameter bytes (0x30-0x3f) and
+		// intermediate bytes (0x20-0x2f) followed by a final
+		// byte (0x40-0x7e).
+		for end++; end < len(data); end++ {
+			b := data[end]
+			if b >= 0x40 && b <= 0x7e {
+				end++
+				complete = true
+				return
+			}
+
+			if b < 0x20 || b > 0x3f {
+				// malformed sequence - let the offending
+				// byte be interpreted on its own.
+				complete = true
+				return
+			}
+		}
+		return
+	case introducer == ']' || introducer == 'P' || introducer == 'X' ||
+		introducer == '^' || introducer == '_':
+		// control strings: terminated by ST (`ESC \`) or, in
+		// practice, by BEL.
+		for end++; end < len(data); end++ {
+			b := data[end]
+			if b == bel {
+				end++
+				complete = true
+				return
+			}
+
+			if b == esc {
+				if end+1 >= len(data) {
+					return
+				}
+
+				if data[end+1] == '\\' {
+					end += 2
+				}
+
+				complete = true
+				return
+			}
+		}
+		return
+	case introducer >= 0x20 && introducer <= 0x2f:
+		// intermediate bytes followed by a final byte
+		// (e.g., `ESC ( B`).
+		for end++; end < len(data); end++ {
+			b := data[end]
+			if b >= 0x30 && b <= 0x7e {
+				end++
+				complete = true
+				return
+			}
+
+			if b < 0x20 || b > 0x2f {
+				complete = true
+				return
+			}
+		}
+		return
+	case introducer >= 0x30 && introducer <= 0x7e:
+		// two-character sequence (e.g., `ESC 7`, `ESC =`).
+		end++
+		complete = true
+		return
+	default:
+		complete = true
+		return
+	}
+}
@@ -0,0 +1,13 @@
+package ansi_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAnsi(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ansi Suite")
+}
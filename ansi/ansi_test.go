@@ -0,0 +1,75 @@
+package ansi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+)
+
+var _ = Describe("Ansi", func() {
+	Describe("Tokenize", func() {
+		It("is empty with empty data", func() {
+			Expect(ansi.Tokenize("")).To(BeEmpty())
+		})
+
+		It("splits text, controls and escape sequences", func() {
+			Expect(ansi.Tokenize("a\x1b[1mbç\r\n")).To(Equal([]ansi.Token{
+				{Kind: ansi.Text, Data: "a"},
+				{Kind: ansi.Escape, Data: "\x1b[1m"},
+				{Kind: ansi.Text, Data: "bç"},
+				{Kind: ansi.Control, Data: "\r"},
+				{Kind: ansi.Control, Data: "\n"},
+			}))
+		})
+
+		It("recognizes control strings terminated by BEL", func() {
+			Expect(ansi.Tokenize("\x1b]2;title\x07a")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b]2;title\x07"},
+				{Kind: ansi.Text, Data: "a"},
+			}))
+		})
+
+		It("recognizes control strings terminated by ST", func() {
+			Expect(ansi.Tokenize("\x1b]2;title\x1b\\a")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b]2;title\x1b\\"},
+				{Kind: ansi.Text, Data: "a"},
+			}))
+		})
+
+		It("recognizes sequences with intermediate bytes", func() {
+			Expect(ansi.Tokenize("\x1b(Bx")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b(B"},
+				{Kind: ansi.Text, Data: "x"},
+			}))
+		})
+
+		It("recognizes two-character sequences", func() {
+			Expect(ansi.Tokenize("\x1b=\x1b>")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b="},
+				{Kind: ansi.Escape, Data: "\x1b>"},
+			}))
+		})
+
+		It("marks unterminated sequences as incomplete", func() {
+			Expect(ansi.Tokenize("a\x1b[1;3")).To(Equal([]ansi.Token{
+				{Kind: ansi.Text, Data: "a"},
+				{Kind: ansi.Escape, Data: "\x1b[1;3", Incomplete: true},
+			}))
+
+			Expect(ansi.Tokenize("\x1b")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b", Incomplete: true},
+			}))
+
+			Expect(ansi.Tokenize("\x1b]2;ti")).To(Equal([]ansi.Token{
+				{Kind: ansi.Escape, Data: "\x1b]2;ti", Incomplete: true},
+			}))
+		})
+	})
+
+	Describe("Strip", func() {
+		It("removes escape sequences", func() {
+			Expect(ansi.Strip("\x1b[1mbold\x1b[0m\r\n")).To(Equal("bold\r\n"))
+		})
+	})
+})
@@ -15,7 +15,11 @@ package cast
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -88,9 +92,11 @@ type Event struct {
 
 	// Type represents the type of the data that's been recorded.
 	//
-	// Two types are possible:
-	// - "o": data written to stdout; and
-	// - "i": data read from stdin.
+	// Four types are possible:
+	// - "o": data written to stdout;
+	// - "i": data read from stdin;
+	// - "m": a marker (e.g., a chapter), labeled by the data; and
+	// - "r": a terminal resize, with the new size (`COLSxROWS`) as data.
 	Type string
 
 	// Data represents the data recorded from the terminal.
@@ -141,8 +147,16 @@ func ValidateEvent(event *Event) (isValid bool, err error) {
 		return
 	}
 
-	if event.Type != "i" && event.Type != "o" {
-		err = errors.Errorf("type must either be `o` or `i`")
+	switch event.Type {
+	case "i", "o", "m":
+	case "r":
+		_, _, err = ParseSize(event.Data)
+		if err != nil {
+			err = errors.Wrapf(err, "malformed resize event")
+			return
+		}
+	default:
+		err = errors.Errorf("type must be one of `o`, `i`, `m` or `r`")
 		return
 	}
 
@@ -150,6 +164,38 @@ func ValidateEvent(event *Event) (isValid bool, err error) {
 	return
 }
 
+// ParseSize parses the data of a resize event (`COLSxROWS`, e.g.,
+// `80x24`).
+func ParseSize(data string) (width, height uint, err error) {
+	cols := strings.Split(data, "x")
+	if len(cols) != 2 {
+		err = errors.Errorf("size must be in the `COLSxROWS` format")
+		return
+	}
+
+	parsedWidth, err := strconv.ParseUint(cols[0], 10, 32)
+	if err != nil || parsedWidth == 0 {
+		err = errors.Errorf("malformed number of columns '%s'", cols[0])
+		return
+	}
+
+	parsedHeight, err := strconv.ParseUint(cols[1], 10, 32)
+	if err != nil || parsedHeight == 0 {
+		err = errors.Errorf("malformed number of rows '%s'", cols[1])
+		return
+	}
+
+	width, height = uint(parsedWidth), uint(parsedHeight)
+	return
+}
+
+// FormatSize formats a terminal size as the data of a resize event
+// (`COLSxROWS`, e.g., `80x24`).
+func FormatSize(width, height uint) string {
+	return strconv.FormatUint(uint64(width), 10) + "x" +
+		strconv.FormatUint(uint64(height), 10)
+}
+
 // ValidateEventStream makes sure that a given set of events (event stream)
 // is valid.
 //
@@ -312,6 +358,100 @@ func Decode(reader io.Reader) (cast *Cast, err error) {
 			Data: data,
 		})
 	}
+}
+
+// DecodeLenient reads the whole contents of the reader passed as argument
+// and unmarshals it into a cast struct, tolerating the problems that
+// `Decode` refuses (e.g., truncated lines left by a crashed recorder).
+//
+// Instead of failing, the problematic parts are either repaired or
+// dropped, with each of them being described by a diagnostic:
+// - unknown header fields are ignored;
+// - malformed event lines are dropped;
+// - events of unknown type (or malformed resizes) are dropped; and
+// - invalid UTF-8 sequences are replaced by U+FFFD.
+//
+// Only a missing or malformed header makes it fail.
+//
+// ps.: the resulting cast is not guaranteed to be valid (e.g., events
+// might still be out of order).
+func DecodeLenient(reader io.Reader) (cast *Cast, diagnostics []Diagnostic, err error) {
+	if reader == nil {
+		err = errors.Errorf("reader must not be nil")
+		return
+	}
+
+	var (
+		headerErr  error
+		headerSeen bool
+	)
+
+	cast = &Cast{
+		EventStream: make([]*Event, 0),
+	}
+	diagnostics = make([]Diagnostic, 0)
+
+	report := func(line int, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:     line,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	err = readLines(reader, func(number int, line string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+
+		if !headerSeen {
+			headerSeen = true
+
+			headerErr = json.Unmarshal([]byte(line), &cast.Header)
+			if headerErr != nil {
+				return
+			}
+
+			decoder := json.NewDecoder(strings.NewReader(line))
+			decoder.DisallowUnknownFields()
+			if decoder.Decode(&Header{}) != nil {
+				report(number, "ignored unknown header fields")
+			}
+			return
+		}
+
+		ev, evErr := decodeEventLine(line)
+		if evErr != nil {
+			report(number, "dropped line: %s", evErr)
+			return
+		}
+
+		_, evErr = ValidateEvent(ev)
+		if evErr != nil {
+			report(number, "dropped event: %s", errors.Cause(evErr))
+			return
+		}
+
+		if !utf8.ValidString(line) {
+			report(number, "replaced invalid UTF-8")
+		}
+
+		cast.EventStream = append(cast.EventStream, ev)
+	})
+	if err != nil {
+		return
+	}
+
+	if !headerSeen {
+		err = errors.Errorf("missing header")
+		return
+	}
+
+	if headerErr != nil {
+		err = errors.Wrapf(headerErr,
+			"couldn't decode header")
+		return
+	}
 
 	return
 }
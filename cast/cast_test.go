@@ -114,7 +114,7 @@ var _ = Describe("Cast", func() {
 				Expect(isValid).NotTo(BeTrue())
 			})
 
-			It("fails if not `i` or `o`", func() {
+			It("fails if not `i`, `o`, `m` or `r`", func() {
 				isValid, err := cast.ValidateEvent(&cast.Event{
 					Type: "abc",
 				})
@@ -122,6 +122,16 @@ var _ = Describe("Cast", func() {
 				Expect(err).NotTo(Succeed())
 				Expect(isValid).NotTo(BeTrue())
 			})
+
+			It("fails if resize doesn't carry a size", func() {
+				isValid, err := cast.ValidateEvent(&cast.Event{
+					Type: "r",
+					Data: "80",
+				})
+
+				Expect(err).NotTo(Succeed())
+				Expect(isValid).NotTo(BeTrue())
+			})
 		})
 
 		It("succeeds if well specified", func() {
@@ -142,6 +152,58 @@ var _ = Describe("Cast", func() {
 
 			Expect(err).To(Succeed())
 			Expect(isValid).To(BeTrue())
+
+			isValid, err = cast.ValidateEvent(&cast.Event{
+				Time: 322,
+				Type: "m",
+				Data: "chapter 1",
+			})
+
+			Expect(err).To(Succeed())
+			Expect(isValid).To(BeTrue())
+
+			isValid, err = cast.ValidateEvent(&cast.Event{
+				Time: 323,
+				Type: "r",
+				Data: "80x24",
+			})
+
+			Expect(err).To(Succeed())
+			Expect(isValid).To(BeTrue())
+		})
+	})
+
+	Describe("ParseSize", func() {
+		It("fails without separator", func() {
+			_, _, err := cast.ParseSize("80")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails with non-numeric values", func() {
+			_, _, err := cast.ParseSize("ax24")
+			Expect(err).NotTo(Succeed())
+
+			_, _, err = cast.ParseSize("80xb")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails with zero values", func() {
+			_, _, err := cast.ParseSize("0x24")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("parses columns and rows", func() {
+			width, height, err := cast.ParseSize("80x24")
+			Expect(err).To(Succeed())
+			Expect(width).To(Equal(uint(80)))
+			Expect(height).To(Equal(uint(24)))
+		})
+
+		It("parses the format of FormatSize", func() {
+			width, height, err := cast.ParseSize(cast.FormatSize(100, 30))
+			Expect(err).To(Succeed())
+			Expect(width).To(Equal(uint(100)))
+			Expect(height).To(Equal(uint(30)))
 		})
 	})
 
@@ -409,4 +471,70 @@ var _ = Describe("Cast", func() {
 			})
 		})
 	})
+	Describe("DecodeLenient", func() {
+		Context("with nil reader", func() {
+			It("fails", func() {
+				_, _, err := cast.DecodeLenient(nil)
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with empty reader", func() {
+			It("fails", func() {
+				_, _, err := cast.DecodeLenient(bytes.NewBufferString(""))
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with malformed header", func() {
+			It("fails", func() {
+				_, _, err := cast.DecodeLenient(bytes.NewBufferString(`{"version":`))
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with a broken event stream", func() {
+			var (
+				decodedCast *cast.Cast
+				diagnostics []cast.Diagnostic
+				err         error
+			)
+
+			BeforeEach(func() {
+				decodedCast, diagnostics, err = cast.DecodeLenient(bytes.NewBufferString(
+					`{"version": 2, "width": 80, "height": 24, "foo": "bar"}
+[1, "o", "a"]
+[2, "x", "b"]
+
+[3, "o", "` + "\xff" + `"]
+[2, "o", "d"]
+[4, "o", "e`))
+			})
+
+			It("succeeds", func() {
+				Expect(err).To(Succeed())
+			})
+
+			It("keeps the header", func() {
+				Expect(decodedCast.Header.Width).To(Equal(uint(80)))
+			})
+
+			It("keeps the events that could be decoded", func() {
+				Expect(decodedCast.EventStream).To(Equal([]*cast.Event{
+					{Time: 1, Type: "o", Data: "a"},
+					{Time: 3, Type: "o", Data: "\ufffd"},
+					{Time: 2, Type: "o", Data: "d"},
+				}))
+			})
+
+			It("describes what has been changed", func() {
+				lines := []int{}
+				for _, diagnostic := range diagnostics {
+					lines = append(lines, diagnostic.Line)
+				}
+
+				Expect(lines).To(Equal([]int{1, 3, 5, 7}))
+			})
+		})
+	})
 })
@@ -0,0 +1,384 @@
+package cast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/pkg/errors"
+)
+
+// Severity indicates how serious a problem found in a cast is.
+type Severity string
+
+const (
+	// SeverityError indicates a problem that makes the cast invalid
+	// (i.e., it can't be decoded or doesn't pass validation).
+	SeverityError Severity = "error"
+
+	// SeverityWarning indicates a problem that doesn't make the cast
+	// invalid but that is likely to affect how it gets played.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic describes a problem found in a cast.
+type Diagnostic struct {
+	// Line is the line number (starting at 1) where the problem
+	// was found.
+	Line int
+
+	// Severity indicates how serious the problem is.
+	Severity Severity
+
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the diagnostic as `line: severity: message`.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d: %s: %s", d.Line, d.Severity, d.Message)
+}
+
+// LintOptions configures which checks are performed by `Lint`.
+type LintOptions struct {
+	// MaxGap is the delay (in seconds) between two consecutive events
+	// above which a gap is reported as suspicious.
+	//
+	// A zero value disables the check.
+	MaxGap float64
+}
+
+// DefaultLintOptions are the options used by `Lint` when no
+// customization is needed.
+var DefaultLintOptions = LintOptions{
+	MaxGap: 60,
+}
+
+// Lint reads a cast line by line, collecting all of the problems that
+// it can find instead of stopping at the first one (like `Decode` and
+// `Validate` do).
+//
+// An error is only returned if reading from `reader` fails.
+func Lint(reader io.Reader, options LintOptions) (diagnostics []Diagnostic, err error) {
+	if reader == nil {
+		err = errors.Errorf("reader must not be nil")
+		return
+	}
+
+	var l = &linter{
+		options:     options,
+		diagnostics: make([]Diagnostic, 0),
+	}
+
+	err = readLines(reader, l.lintLine)
+	if err != nil {
+		return
+	}
+
+	l.finish()
+	diagnostics = l.diagnostics
+	return
+}
+
+// HasErrors verifies whether any of the diagnostics has error severity.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+type linter struct {
+	options     LintOptions
+	diagnostics []Diagnostic
+
+	headerSeen bool
+	width      uint
+	height     uint
+
+	hasLastTime bool
+	lastTime    float64
+
+	// pending holds an escape sequence that started in a previous
+	// output event and hasn't been terminated yet.
+	pending     string
+	pendingLine int
+}
+
+func (l *linter) report(line int, severity Severity, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintLine(number int, line string) {
+	if strings.TrimSpace(line) == "" {
+		l.report(number, SeverityWarning, "empty line")
+		return
+	}
+
+	if !utf8.ValidString(line) {
+		l.report(number, SeverityError, "invalid UTF-8")
+	}
+
+	if !l.headerSeen {
+		l.headerSeen = true
+		l.lintHeader(number, line)
+		return
+	}
+
+	l.lintEvent(number, line)
+}
+
+func (l *linter) lintHeader(number int, line string) {
+	var header Header
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&header)
+	if err != nil {
+		l.report(number, SeverityError, "malformed header: %s", err)
+		return
+	}
+
+	if header.Version != 2 {
+		l.report(number, SeverityError,
+			"unsupported version %d (must be 2)", header.Version)
+	}
+
+	if header.Width == 0 {
+		l.report(number, SeverityError, "width must be greater than 0")
+	}
+
+	if header.Height == 0 {
+		l.report(number, SeverityError, "height must be greater than 0")
+	}
+
+	if header.IdleTimeLimit < 0 {
+		l.report(number, SeverityError, "idle_time_limit must not be negative")
+	}
+
+	l.width = header.Width
+	l.height = header.Height
+}
+
+func (l *linter) lintEvent(number int, line string) {
+	ev, err := decodeEventLine(line)
+	if err != nil {
+		l.report(number, SeverityError, "%s", err)
+		return
+	}
+
+	if ev.Time < 0 {
+		l.report(number, SeverityError, "negative time %g", ev.Time)
+	}
+
+	if l.hasLastTime {
+		if ev.Time < l.lastTime {
+			l.report(number, SeverityError,
+				"out-of-order time %g (previous event at %g)",
+				ev.Time, l.lastTime)
+		}
+
+		if l.options.MaxGap > 0 && ev.Time-l.lastTime > l.options.MaxGap {
+			l.report(number, SeverityWarning,
+				"suspicious gap of %gs (previous event at %g)",
+				ev.Time-l.lastTime, l.lastTime)
+		}
+	}
+
+	if !l.hasLastTime || ev.Time > l.lastTime {
+		l.lastTime = ev.Time
+	}
+	l.hasLastTime = true
+
+	switch ev.Type {
+	case "o":
+		l.lintOutput(number, ev.Data)
+	case "i", "m":
+	case "r":
+		l.lintResize(number, ev.Data)
+	default:
+		l.report(number, SeverityError, "unknown event type %q", ev.Type)
+	}
+}
+
+func (l *linter) lintResize(number int, data string) {
+	width, height, err := ParseSize(data)
+	if err != nil {
+		l.report(number, SeverityError, "malformed resize event: %s", err)
+		return
+	}
+
+	l.width = width
+	l.height = height
+}
+
+func (l *linter) lintOutput(number int, data string) {
+	var (
+		pending     = l.pending
+		pendingLine = l.pendingLine
+	)
+
+	l.pending = ""
+
+	for idx, token := range ansi.Tokenize(pending + data) {
+		if token.Incomplete {
+			l.pending = token.Data
+			l.pendingLine = number
+			if idx == 0 && pending != "" {
+				l.pendingLine = pendingLine
+			}
+			return
+		}
+
+		if token.Kind != ansi.Escape {
+			continue
+		}
+
+		row, col, ok := cursorPosition(token.Data)
+		if !ok || l.width == 0 || l.height == 0 {
+			continue
+		}
+
+		if row > l.height || col > l.width {
+			l.report(number, SeverityWarning,
+				"cursor moved to row %d, column %d outside of the %dx%d terminal",
+				row, col, l.width, l.height)
+		}
+	}
+}
+
+func (l *linter) finish() {
+	if !l.headerSeen {
+		l.report(1, SeverityError, "missing header")
+		return
+	}
+
+	if l.pending != "" {
+		l.report(l.pendingLine, SeverityWarning,
+			"incomplete escape sequence %q at the end of the stream",
+			l.pending)
+	}
+}
+
+// cursorPosition extracts the coordinates (1-based) from a CUP
+// (`ESC [ row ; col H`) or HVP (`ESC [ row ; col f`) sequence.
+func cursorPosition(sequence string) (row, col uint, ok bool) {
+	if len(sequence) < 3 || sequence[1] != '[' {
+		return
+	}
+
+	final := sequence[len(sequence)-1]
+	if final != 'H' && final != 'f' {
+		return
+	}
+
+	params := strings.Split(sequence[2:len(sequence)-1], ";")
+	if len(params) > 2 {
+		return
+	}
+
+	row, col = 1, 1
+	for idx, param := range params {
+		if param == "" {
+			continue
+		}
+
+		value, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return
+		}
+
+		if idx == 0 {
+			row = uint(value)
+		} else {
+			col = uint(value)
+		}
+	}
+
+	ok = true
+	return
+}
+
+// readLines reads `reader` line by line until EOF, calling `fn` with
+// each line (including its line terminator) and its number (starting
+// at 1).
+func readLines(reader io.Reader, fn func(number int, line string)) (err error) {
+	var (
+		buffered = bufio.NewReader(reader)
+		line     string
+		number   int
+	)
+
+	for {
+		line, err = buffered.ReadString('\n')
+		if err != nil && err != io.EOF {
+			err = errors.Wrapf(err, "failed to read line %d", number+1)
+			return
+		}
+
+		if err == io.EOF && line == "" {
+			err = nil
+			return
+		}
+
+		number++
+		fn(number, line)
+
+		if err == io.EOF {
+			err = nil
+			return
+		}
+	}
+}
+
+// decodeEventLine decodes a single line of the event stream.
+func decodeEventLine(line string) (ev *Event, err error) {
+	var raw []json.RawMessage
+
+	err = json.Unmarshal([]byte(line), &raw)
+	if err != nil {
+		err = errors.Wrapf(err, "malformed event")
+		return
+	}
+
+	if len(raw) != 3 {
+		err = errors.Errorf(
+			"event must have 3 elements, found %d", len(raw))
+		return
+	}
+
+	ev = new(Event)
+
+	err = json.Unmarshal(raw[0], &ev.Time)
+	if err != nil {
+		err = errors.Errorf("event time is not a number")
+		return
+	}
+
+	err = json.Unmarshal(raw[1], &ev.Type)
+	if err != nil {
+		err = errors.Errorf("event type is not a string")
+		return
+	}
+
+	err = json.Unmarshal(raw[2], &ev.Data)
+	if err != nil {
+		err = errors.Errorf("event data is not a string")
+		return
+	}
+
+	return
+}
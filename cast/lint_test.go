@@ -0,0 +1,132 @@
+package cast_test
+
+import (
+	"bytes"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var (
+		input       string
+		diagnostics []cast.Diagnostic
+		err         error
+	)
+
+	JustBeforeEach(func() {
+		diagnostics, err = cast.Lint(bytes.NewBufferString(input),
+			cast.DefaultLintOptions)
+		Expect(err).To(Succeed())
+	})
+
+	Context("with nil reader", func() {
+		It("fails", func() {
+			_, err := cast.Lint(nil, cast.DefaultLintOptions)
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("with empty input", func() {
+		BeforeEach(func() {
+			input = ""
+		})
+
+		It("reports the missing header", func() {
+			Expect(diagnostics).To(Equal([]cast.Diagnostic{
+				{Line: 1, Severity: cast.SeverityError, Message: "missing header"},
+			}))
+		})
+	})
+
+	Context("with a well formed cast", func() {
+		BeforeEach(func() {
+			input = `{"version": 2, "width": 80, "height": 24}
+[1, "o", "\u001b[1mbold"]
+[2, "i", "a"]
+[3, "m", "chapter"]
+[4, "r", "100x30"]
+[5, "o", "\u001b[30;100H"]
+`
+		})
+
+		It("reports nothing", func() {
+			Expect(diagnostics).To(BeEmpty())
+		})
+	})
+
+	Context("with an invalid header", func() {
+		BeforeEach(func() {
+			input = `{"version": 1, "width": 0, "height": 0}
+[1, "o", "a"]`
+		})
+
+		It("reports all the problems", func() {
+			Expect(diagnostics).To(HaveLen(3))
+			for _, diagnostic := range diagnostics {
+				Expect(diagnostic.Line).To(Equal(1))
+				Expect(diagnostic.Severity).To(Equal(cast.SeverityError))
+			}
+		})
+
+		It("is considered to have errors", func() {
+			Expect(cast.HasErrors(diagnostics)).To(BeTrue())
+		})
+	})
+
+	Context("with problematic events", func() {
+		BeforeEach(func() {
+			input = `{"version": 2, "width": 80, "height": 24}
+[1, "o", "a"]
+[-1, "o", "b"]
+[2, "x", "c"]
+[3, "o", "` + "\xff" + `"]
+[300, "o", "\u001b[25;1H"]
+[301, "o"
+[302, "r", "80"]
+[303, "o", "\u001b["]
+[304, "o", "1"]
+`
+		})
+
+		It("reports all of them with line numbers", func() {
+			Expect(diagnostics).To(HaveLen(9))
+
+			lines := []int{}
+			severities := []cast.Severity{}
+			for _, diagnostic := range diagnostics {
+				lines = append(lines, diagnostic.Line)
+				severities = append(severities, diagnostic.Severity)
+			}
+
+			Expect(lines).To(Equal([]int{3, 3, 4, 5, 6, 6, 7, 8, 9}))
+			Expect(severities).To(Equal([]cast.Severity{
+				cast.SeverityError,   // negative time
+				cast.SeverityError,   // out-of-order
+				cast.SeverityError,   // unknown type
+				cast.SeverityError,   // invalid utf-8
+				cast.SeverityWarning, // suspicious gap
+				cast.SeverityWarning, // cursor out of bounds
+				cast.SeverityError,   // malformed event
+				cast.SeverityError,   // malformed resize
+				cast.SeverityWarning, // incomplete sequence
+			}))
+		})
+	})
+
+	Context("with a truncated last line", func() {
+		BeforeEach(func() {
+			input = `{"version": 2, "width": 80, "height": 24}
+[1, "o", "a"]
+[2, "o", "b`
+		})
+
+		It("reports it", func() {
+			Expect(diagnostics).To(HaveLen(1))
+			Expect(diagnostics[0].Line).To(Equal(3))
+			Expect(diagnostics[0].Severity).To(Equal(cast.SeverityError))
+		})
+	})
+})
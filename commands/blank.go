@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Blank = cli.Command{
+	Name: "blank",
+	Usage: `Hides what was printed while keeping the timing.
+
+   The data of the selected input and output events is removed (or
+   replaced by '--placeholder' at the beginning of each section),
+   while their timestamps are kept intact. Differently from 'cut', the
+   pacing of the cast doesn't change.
+
+   Events are selected by time ('--window start,end'), by their
+   position ('--from-index' and '--to-index'), by the sections that
+   start at markers ('--marker label'), by their data ('--match
+   regexp', padded with '--before' and '--after' seconds) and by type
+   ('--type o').
+
+   With '--keep-sequences', only the text of output events is removed,
+   keeping line breaks, screen clears and cursor movements so that the
+   rest of the screen stays as it was.
+
+   The number of blanked events is reported to stderr.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Hide everything printed between 12.2s and 15.3s:
+
+     asciinema-edit blank --window 12.2,15.3 ./123.cast
+
+   Hide the section that starts at the "credentials" marker, showing a
+   placeholder instead:
+
+     asciinema-edit blank \
+       --marker credentials \
+       --placeholder '[redacted]' \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    blankAction,
+	Flags: withSelection([]cli.Flag{
+		cli.StringFlag{
+			Name:  "placeholder",
+			Usage: "message printed in place of each blanked section",
+		},
+		cli.BoolFlag{
+			Name:  "keep-sequences",
+			Usage: "keep control characters and escape sequences",
+		},
+	}, cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the modified contents to",
+	}),
+}
+
+type blankTransformation struct {
+	selection editor.Selection
+	options   editor.BlankOptions
+	count     int
+}
+
+func (t *blankTransformation) Transform(c *cast.Cast) (err error) {
+	t.count, err = editor.Blank(c, t.selection, t.options)
+	return
+}
+
+func blankAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &blankTransformation{
+			options: editor.BlankOptions{
+				Placeholder:   c.String("placeholder"),
+				KeepSequences: c.Bool("keep-sequences"),
+			},
+		}
+		selected bool
+	)
+
+	transformation.selection, selected, err = parseSelection(c)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if !selected {
+		err = cli.NewExitError("a selection must be specified.", 1)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "blanked %d events\n", transformation.count)
+	return
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Compact = cli.Command{
+	Name: "compact",
+	Usage: `Merges tiny events to shrink a cast.
+
+   Consecutive output (or input) events that happen less than
+   '--threshold' seconds after the first event of the group they'd be
+   merged into are combined into a single event, so that no output gets
+   displayed more than the threshold earlier than it originally was.
+
+   Events are never merged in a way that leaves a character split
+   across events.
+
+   The number of events and the size of the cast before and after
+   compacting are reported to stderr.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Merge the events that happen within 10ms of each other:
+
+     asciinema-edit compact ./123.cast
+
+   Merge the events that happen within 50ms of each other:
+
+     asciinema-edit compact --threshold 0.05 ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    compactAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "threshold",
+			Value: 0.01,
+			Usage: "maximum time (in seconds) between merged events",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type compactTransformation struct {
+	threshold float64
+	report    editor.CompactReport
+	before    int64
+	after     int64
+}
+
+func (t *compactTransformation) Transform(c *cast.Cast) (err error) {
+	t.before, err = encodedSize(c)
+	if err != nil {
+		return
+	}
+
+	t.report, err = editor.Compact(c, t.threshold)
+	if err != nil {
+		return
+	}
+
+	t.after, err = encodedSize(c)
+	return
+}
+
+func compactAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &compactTransformation{
+			threshold: c.Float64("threshold"),
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "events: %d -> %d (%d merged)\n",
+		transformation.report.Before, transformation.report.After,
+		transformation.report.Merged())
+
+	fmt.Fprintf(os.Stderr, "size: %d -> %d bytes (%.1f%% smaller)\n",
+		transformation.before, transformation.after,
+		reduction(transformation.before, transformation.after))
+
+	return
+}
+
+// reduction computes how much smaller (in percentage) `after` is when
+// compared to `before`.
+func reduction(before, after int64) float64 {
+	if before == 0 {
+		return 0
+	}
+
+	return float64(before-after) / float64(before) * 100
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Concat = cli.Command{
+	Name: "concat",
+	Usage: `Joins multiple casts into a single one.
+
+   The casts specified as positional arguments are joined in order,
+   with the events of each cast happening after the last event of the
+   previous one (plus the delay specified in '--gap').
+
+   The resulting cast has the header of the first cast. When the
+   terminal sizes differ, '--size' determines what to do:
+
+   - fail: abort the concatenation (default);
+   - max: use the biggest width and height, letterboxing the smaller
+     contents; or
+   - resize: emit resize events at the joins.
+
+   With '--markers', a marker event labeled after the name of the file
+   is added at each join.
+
+   The resulting cast is either written to a file specified in the
+   '--out' flag or to stdout (default).
+
+EXAMPLES:
+   Join three takes, with a one second pause between them:
+
+     asciinema-edit concat \
+       --gap 1 \
+       --out ./demo.cast \
+       ./take-1.cast ./take-2.cast ./take-3.cast
+
+   Join casts recorded with different terminal sizes, marking where
+   each one starts:
+
+     asciinema-edit concat \
+       --size max \
+       --markers \
+       ./intro.cast ./demo.cast`,
+	ArgsUsage: "filename...",
+	Action:    concatAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "gap",
+			Usage: "delay (in seconds) between the casts",
+		},
+		cli.StringFlag{
+			Name:  "size",
+			Value: string(editor.SizeFail),
+			Usage: "size mismatch policy (fail, max or resize)",
+		},
+		cli.BoolFlag{
+			Name:  "markers",
+			Usage: "add a marker event at each join",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the joined cast to",
+		},
+	},
+}
+
+func concatAction(c *cli.Context) (err error) {
+	var (
+		inputs  = c.Args()
+		output  = c.String("out")
+		casts   = make([]*cast.Cast, 0, len(inputs))
+		res     *cast.Cast
+		options = editor.ConcatOptions{
+			Gap:     c.Float64("gap"),
+			Markers: c.Bool("markers"),
+			Labels:  make([]string, 0, len(inputs)),
+		}
+	)
+
+	if len(inputs) < 2 {
+		err = cli.NewExitError("at least two casts must be specified.", 1)
+		return
+	}
+
+	options.Policy, err = editor.ParseSizePolicy(c.String("size"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for _, input := range inputs {
+		var data *cast.Cast
+
+		data, err = readCast(input)
+		if err != nil {
+			err = cli.NewExitError(errors.Wrapf(err,
+				"failed to read cast %s", input), 1)
+			return
+		}
+
+		casts = append(casts, data)
+		options.Labels = append(options.Labels, filepath.Base(input))
+	}
+
+	res, err = editor.Concat(casts, options)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	err = writeCast(output, res)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
@@ -11,6 +11,14 @@ var Cut = cli.Command{
 	Name: "cut",
 	Usage: `Removes a certain range of time frames.
 
+   Instead of exact timestamps, the events to remove can be selected
+   by time ('--window start,end'), by their position in the event
+   stream ('--from-index' and '--to-index', both included, which is
+   useful when multiple events share the same timestamp), by the
+   sections that start at markers ('--marker label'), by their data
+   ('--match regexp', padded with '--before' and '--after' seconds) and
+   by type ('--type o'). Events matched by any of them are removed.
+
    If no file name is specified as a positional argument, a cast is
    expected to be served via stdin.
 
@@ -31,31 +39,51 @@ EXAMPLES:
 
      asciinema-edit cut \
        --start=12.2 --end=12.2 \
+       1234.cast
+
+   Remove the events at positions 10 to 12 (as listed by
+   'info --events'), even if other events share their timestamps.
+
+     asciinema-edit cut \
+       --from-index=10 --to-index=12 \
+       1234.cast
+
+   Remove every event that prints a token, as well as anything printed
+   in the following half second.
+
+     asciinema-edit cut \
+       --match='token: [a-z0-9]+' \
+       --after=0.5 \
        1234.cast`,
 	ArgsUsage: "[filename]",
 	Action:    cutAction,
-	Flags: []cli.Flag{
+	Flags: withSelection([]cli.Flag{
 		cli.Float64Flag{
 			Name:  "start",
-			Usage: "initial frame timestamp (required)",
+			Usage: "initial frame timestamp",
 		},
 		cli.Float64Flag{
 			Name:  "end",
-			Usage: "final frame timestamp (required)",
+			Usage: "final frame timestamp",
 		},
-		cli.StringFlag{
-			Name:  "out",
-			Usage: "file to write the modified contents to",
-		},
-	},
+	}, cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the modified contents to",
+	}),
 }
 
 type cutTransformation struct {
-	from float64
-	to   float64
+	from      float64
+	to        float64
+	selection *editor.Selection
 }
 
 func (t *cutTransformation) Transform(c *cast.Cast) (err error) {
+	if t.selection != nil {
+		err = editor.CutSelection(c, *t.selection)
+		return
+	}
+
 	err = editor.Cut(c, t.from, t.to)
 	return
 }
@@ -70,6 +98,16 @@ func cutAction(c *cli.Context) (err error) {
 		}
 	)
 
+	selection, selected, err := parseSelection(c)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if selected {
+		transformation.selection = &selection
+	}
+
 	t, err := transformer.New(transformation, input, output)
 	if err != nil {
 		err = cli.NewExitError(err, 1)
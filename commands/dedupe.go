@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Dedupe = cli.Command{
+	Name: "dedupe",
+	Usage: `Removes output events that don't change the screen.
+
+   The cast is rendered through a terminal emulator and every output
+   event that leaves the terminal exactly as it was (e.g., a progress
+   bar redrawing the same content) is removed. The timestamps of the
+   remaining events are kept intact. Events that only carry sequences
+   that don't affect the screen (e.g., window titles) are removed too,
+   while events carrying sequences that the emulator doesn't interpret
+   are always kept.
+
+   With '--spinners', consecutive output events that only redraw the
+   same character and repeat themselves (the frames of a spinner) are
+   collapsed: the first frame is held until the spinner ends.
+
+   The number of removed events and the size of the cast before and
+   after deduplicating are reported to stderr.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Remove the redundant redraws from "123.cast":
+
+     asciinema-edit dedupe ./123.cast
+
+   Also hold the spinners still:
+
+     asciinema-edit dedupe --spinners ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    dedupeAction,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "spinners",
+			Usage: "collapse spinner frames into a single held frame",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type dedupeTransformation struct {
+	options editor.DedupeOptions
+	report  editor.DedupeReport
+	before  int64
+	after   int64
+}
+
+func (t *dedupeTransformation) Transform(c *cast.Cast) (err error) {
+	t.before, err = encodedSize(c)
+	if err != nil {
+		return
+	}
+
+	t.report, err = editor.Dedupe(c, t.options)
+	if err != nil {
+		return
+	}
+
+	t.after, err = encodedSize(c)
+	return
+}
+
+func dedupeAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &dedupeTransformation{
+			options: editor.DedupeOptions{
+				Spinners: c.Bool("spinners"),
+			},
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "removed %d events (%d no-ops, %d spinner frames)\n",
+		transformation.report.Removed(), transformation.report.NoOps,
+		transformation.report.SpinnerFrames)
+
+	fmt.Fprintf(os.Stderr, "size: %d -> %d bytes (%.1f%% smaller)\n",
+		transformation.before, transformation.after,
+		reduction(transformation.before, transformation.after))
+
+	return
+}
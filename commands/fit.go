@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Fit = cli.Command{
+	Name: "fit",
+	Usage: `Stretches or compresses a cast to fit a target duration.
+
+   The delays between events are scaled so that the cast lasts exactly
+   the duration specified in '--duration' (e.g., '90s'). How delays get
+   scaled is determined by '--strategy':
+
+   - uniform: multiply all delays by the same factor (default); or
+   - pauses: shorten the longest pauses first (stretching is performed
+     uniformly).
+
+   With '--compressible start,end', only the delays within the given
+   ranges (in the timestamps of the original cast) are scaled, keeping
+   the timing of everything else.
+
+   The effective factor applied to each segment is reported to stderr.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Make "123.cast" last exactly a minute and a half:
+
+     asciinema-edit fit --duration 90s ./123.cast
+
+   Fit the cast in 5 minutes by shortening the longest pauses:
+
+     asciinema-edit fit \
+       --duration 5m \
+       --strategy pauses \
+       ./123.cast
+
+   Fit the cast in 2 minutes changing only the installation steps:
+
+     asciinema-edit fit \
+       --duration 2m \
+       --compressible 10.5,42.1 \
+       --compressible 60,75.3 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    fitAction,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "duration",
+			Usage: "total duration of the resulting cast",
+		},
+		cli.StringFlag{
+			Name:  "strategy",
+			Value: string(editor.FitUniform),
+			Usage: "how delays are scaled (uniform or pauses)",
+		},
+		cli.StringSliceFlag{
+			Name:  "compressible",
+			Usage: "range (start,end) whose delays can be scaled",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type fitTransformation struct {
+	options  editor.FitOptions
+	segments []editor.FitSegment
+}
+
+func (t *fitTransformation) Transform(c *cast.Cast) (err error) {
+	t.segments, err = editor.Fit(c, t.options)
+	return
+}
+
+// ParseTimeRange takes an input string that represents a time range
+// and converts it into a TimeRange instance.
+//
+// The format is `start,end` (e.g., `1.2,5.3`).
+//
+// Fails if the input can't be converted to a TimeRange.
+func ParseTimeRange(input string) (res editor.TimeRange, err error) {
+	cols := strings.Split(input, ",")
+
+	if len(cols) != 2 {
+		err = errors.Errorf(
+			"invalid range format: must be `start,end`")
+		return
+	}
+
+	res.From, err = strconv.ParseFloat(cols[0], 64)
+	if err != nil {
+		err = errors.Errorf(
+			"malformed range: first element is not a float '%s'", cols[0])
+		return
+	}
+
+	res.To, err = strconv.ParseFloat(cols[1], 64)
+	if err != nil {
+		err = errors.Errorf(
+			"malformed range: second element is not a float '%s'", cols[1])
+		return
+	}
+
+	if res.From < 0 {
+		err = errors.Errorf(
+			"constraint not verified: start >= 0")
+		return
+	}
+
+	if res.From >= res.To {
+		err = errors.Errorf(
+			"constraint not verified: start < end")
+		return
+	}
+
+	return
+}
+
+func fitAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &fitTransformation{
+			options: editor.FitOptions{
+				Duration:     c.Duration("duration").Seconds(),
+				Compressible: make([]editor.TimeRange, 0),
+			},
+		}
+	)
+
+	if transformation.options.Duration <= 0 {
+		err = cli.NewExitError("a positive duration must be specified.", 1)
+		return
+	}
+
+	transformation.options.Strategy, err = editor.ParseFitStrategy(
+		c.String("strategy"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for _, input := range c.StringSlice("compressible") {
+		var tRange editor.TimeRange
+
+		tRange, err = ParseTimeRange(input)
+		if err != nil {
+			err = cli.NewExitError(errors.Wrapf(err,
+				"failed to parse range %s", input), 1)
+			return
+		}
+
+		transformation.options.Compressible = append(
+			transformation.options.Compressible, tRange)
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for _, segment := range transformation.segments {
+		fmt.Fprintf(os.Stderr, "%s-%s: x%.4g\n",
+			formatSeconds(segment.From), formatSeconds(segment.To),
+			segment.Factor)
+	}
+
+	return
+}
@@ -0,0 +1,50 @@
+package commands_test
+
+import (
+	"github.com/cirocosta/asciinema-edit/commands"
+	"github.com/cirocosta/asciinema-edit/editor"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseTimeRange", func() {
+	Context("having the wrong number of elements", func() {
+		It("fails with a single element", func() {
+			_, err := commands.ParseTimeRange("1")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails with three elements", func() {
+			_, err := commands.ParseTimeRange("1,2,3")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having invalid chars", func() {
+		It("fails if non-numeric", func() {
+			_, err := commands.ParseTimeRange("1,a")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having invalid bounds", func() {
+		It("fails if start is negative", func() {
+			_, err := commands.ParseTimeRange("-1,2")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails if start >= end", func() {
+			_, err := commands.ParseTimeRange("2,2")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having a valid entry", func() {
+		It("parses start and end", func() {
+			tRange, err := commands.ParseTimeRange("1.5,3")
+			Expect(err).To(Succeed())
+			Expect(tRange).To(Equal(editor.TimeRange{From: 1.5, To: 3}))
+		})
+	})
+})
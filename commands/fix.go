@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Fix = cli.Command{
+	Name: "fix",
+	Usage: `Repairs a broken cast.
+
+   Casts left by crashed recorders frequently can't be processed by
+   the other commands: they might have truncated lines, out-of-order
+   timestamps or invalid UTF-8.
+
+   The cast is read leniently, with:
+
+   - malformed lines (e.g., a truncated last line) and events of unknown
+     types being dropped;
+   - invalid UTF-8 sequences being replaced by U+FFFD; and
+   - negative timestamps being set to zero.
+
+   Events with out-of-order timestamps have their timestamps raised to
+   the one of the previous event (or, with '--sort', get moved to the
+   position that their timestamps indicate).
+
+   Every change is reported to stderr.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Repair the cast "123.cast", saving the result to "fixed.cast":
+
+     asciinema-edit fix --out ./fixed.cast ./123.cast
+
+   Repair the cast, sorting events by time and removing duplicated
+   events:
+
+     asciinema-edit fix \
+       --sort \
+       --drop-duplicates \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    fixAction,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "sort",
+			Usage: "sort out-of-order events instead of raising their timestamps",
+		},
+		cli.BoolFlag{
+			Name:  "drop-duplicates",
+			Usage: "remove events equal to the event that precedes them",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+func fixAction(c *cli.Context) (err error) {
+	var (
+		input       = c.Args().First()
+		output      = c.String("out")
+		name        = input
+		file        = os.Stdin
+		data        *cast.Cast
+		diagnostics []cast.Diagnostic
+		report      editor.FixReport
+		options     = editor.FixOptions{
+			Sort:           c.Bool("sort"),
+			DropDuplicates: c.Bool("drop-duplicates"),
+		}
+	)
+
+	if input != "" {
+		file, err = os.Open(input)
+		if err != nil {
+			err = cli.NewExitError(errors.Wrapf(err,
+				"failed to open input file %s", input), 1)
+			return
+		}
+		defer file.Close()
+	} else {
+		name = "<stdin>"
+	}
+
+	data, diagnostics, err = cast.DecodeLenient(file)
+	if err != nil {
+		err = cli.NewExitError(errors.Wrapf(err,
+			"failed to decode cast from input"), 1)
+		return
+	}
+
+	report, err = editor.Fix(data, options)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	_, err = cast.Validate(data)
+	if err != nil {
+		err = cli.NewExitError(errors.Wrapf(err,
+			"couldn't repair cast"), 1)
+		return
+	}
+
+	err = writeCast(output, data)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n",
+			name, diagnostic.Line, diagnostic.Message)
+	}
+
+	if report.NegativeTimes > 0 {
+		fmt.Fprintf(os.Stderr, "set %d negative timestamp(s) to zero\n",
+			report.NegativeTimes)
+	}
+
+	if report.OutOfOrder > 0 {
+		action := "raised the timestamp of"
+		if options.Sort {
+			action = "moved"
+		}
+
+		fmt.Fprintf(os.Stderr, "%s %d out-of-order event(s)\n",
+			action, report.OutOfOrder)
+	}
+
+	if report.Duplicates > 0 {
+		fmt.Fprintf(os.Stderr, "removed %d duplicated event(s)\n",
+			report.Duplicates)
+	}
+
+	if len(diagnostics) == 0 && !report.Changed() {
+		fmt.Fprintln(os.Stderr, "nothing to fix")
+	}
+
+	return
+}
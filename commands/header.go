@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Header = cli.Command{
+	Name: "header",
+	Usage: `Views or edits the cast header metadata.
+
+   Without '--set' or '--unset', the header fields that are set get
+   printed (one 'key=value' per line).
+
+   The keys available are: width, height, timestamp, command, title,
+   idle_time_limit, theme.fg, theme.bg, theme.palette, env.SHELL and
+   env.TERM.
+
+   Values are validated before being set: sizes must be positive
+   integers, colors must be in the '#rrggbb' (or '#rgb') format and
+   palettes must have 8 or 16 colors separated by a colon.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   View the header of the cast "123.cast":
+
+     asciinema-edit header ./123.cast
+
+   Set the title and background color, removing the SHELL
+   environment variable:
+
+     asciinema-edit header \
+       --set title="My demo" \
+       --set theme.bg=#000 \
+       --unset env.SHELL \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    headerAction,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "set",
+			Usage: "header field to set (key=value)",
+		},
+		cli.StringSliceFlag{
+			Name:  "unset",
+			Usage: "header field to clear (key)",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type headerTransformation struct {
+	set   [][2]string
+	unset []string
+}
+
+func (t *headerTransformation) Transform(c *cast.Cast) (err error) {
+	for _, field := range t.set {
+		err = editor.SetHeader(c, field[0], field[1])
+		if err != nil {
+			return
+		}
+	}
+
+	for _, key := range t.unset {
+		err = editor.UnsetHeader(c, key)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func headerAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &headerTransformation{
+			set:   make([][2]string, 0),
+			unset: c.StringSlice("unset"),
+		}
+	)
+
+	for _, field := range c.StringSlice("set") {
+		cols := strings.SplitN(field, "=", 2)
+		if len(cols) != 2 {
+			err = cli.NewExitError(errors.Errorf(
+				"malformed field '%s': must be `key=value`", field), 1)
+			return
+		}
+
+		transformation.set = append(transformation.set,
+			[2]string{cols[0], cols[1]})
+	}
+
+	if len(transformation.set) == 0 && len(transformation.unset) == 0 {
+		err = viewHeader(input)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
+
+func viewHeader(input string) (err error) {
+	data, err := readCast(input)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Printf("version=%d\n", data.Header.Version)
+
+	for _, key := range editor.HeaderKeys {
+		value, _ := editor.GetHeader(data, key)
+		if value == "" {
+			continue
+		}
+
+		fmt.Printf("%s=%s\n", key, value)
+	}
+
+	return
+}
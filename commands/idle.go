@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var BakeIdle = cli.Command{
+	Name: "bake-idle",
+	Usage: `Applies the idle time limit to the timestamps of a cast.
+
+   The 'idle_time_limit' header field is only honored by players, thus
+   other tools that consume the cast (e.g., GIF converters) still play
+   the original delays. This command shortens every delay longer than
+   the limit permanently, just like players do.
+
+   The limit in the header is used, unless one is specified in
+   '--limit'. Once applied, the header field gets cleared, unless
+   '--keep' is specified (keeping it as is) or a new limit is set with
+   '--set-limit'.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Bake the idle time limit of "123.cast" into its timestamps:
+
+     asciinema-edit bake-idle ./123.cast
+
+   Limit delays to 3s, letting players further limit them to 1s:
+
+     asciinema-edit bake-idle \
+       --limit 3 \
+       --set-limit 1 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    bakeIdleAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "limit",
+			Usage: "idle time limit to apply (default: header limit)",
+		},
+		cli.BoolFlag{
+			Name:  "keep",
+			Usage: "keep the idle time limit in the header",
+		},
+		cli.Float64Flag{
+			Name:  "set-limit",
+			Usage: "idle time limit to set in the header",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type bakeIdleTransformation struct {
+	options editor.IdleLimitOptions
+}
+
+func (t *bakeIdleTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.BakeIdleLimit(c, t.options)
+	return
+}
+
+func bakeIdleAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &bakeIdleTransformation{
+			options: editor.IdleLimitOptions{
+				Limit:    c.Float64("limit"),
+				Keep:     c.Bool("keep"),
+				NewLimit: c.Float64("set-limit"),
+			},
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/stats"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Info = cli.Command{
+	Name: "info",
+	Usage: `Summarizes the contents of a cast.
+
+   Reports the header details, the duration, the number of events and
+   bytes per event type, the longest periods of idleness, the typing
+   speed (based on the input events) and a histogram of the delays
+   between events.
+
+   The delay distribution is helpful when deciding which ranges to
+   use with the 'quantize' command.
+
+   With '--events', every event is listed instead, along with its
+   position in the event stream (as used by '--from-index' and
+   '--to-index' in other commands).
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+EXAMPLES:
+   Summarize the cast "123.cast":
+
+     asciinema-edit info ./123.cast
+
+   Summarize the cast showing its 10 longest gaps:
+
+     asciinema-edit info --gaps 10 ./123.cast
+
+   List the events of the cast "123.cast":
+
+     asciinema-edit info --events ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    infoAction,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "gaps",
+			Value: 5,
+			Usage: "number of longest gaps to show",
+		},
+		cli.BoolFlag{
+			Name:  "events",
+			Usage: "list all events along with their positions",
+		},
+	},
+}
+
+// eventDataWidth is the maximum number of characters used to show the
+// data of an event in the event listing.
+const eventDataWidth = 60
+
+// histogramWidth is the maximum number of characters used to
+// draw a bar in the delay histogram.
+const histogramWidth = 40
+
+func infoAction(c *cli.Context) (err error) {
+	var (
+		input   = c.Args().First()
+		gaps    = c.Int("gaps")
+		data    *cast.Cast
+		summary *stats.Summary
+	)
+
+	data, err = readCast(input)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if c.Bool("events") {
+		writeEvents(os.Stdout, data)
+		return
+	}
+
+	summary, err = stats.Summarize(data, gaps)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	err = writeInfo(os.Stdout, &data.Header, summary)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
+
+func writeInfo(w io.Writer, header *cast.Header, summary *stats.Summary) (err error) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "HEADER")
+	fmt.Fprintf(tw, "  version:\t%d\n", header.Version)
+	fmt.Fprintf(tw, "  size:\t%dx%d\n", header.Width, header.Height)
+	if header.Timestamp != 0 {
+		fmt.Fprintf(tw, "  timestamp:\t%d (%s)\n", header.Timestamp,
+			time.Unix(int64(header.Timestamp), 0).UTC().Format(time.RFC3339))
+	}
+	if header.Title != "" {
+		fmt.Fprintf(tw, "  title:\t%s\n", header.Title)
+	}
+	if header.Command != "" {
+		fmt.Fprintf(tw, "  command:\t%s\n", header.Command)
+	}
+	if header.IdleTimeLimit != 0 {
+		fmt.Fprintf(tw, "  idle time limit:\t%s\n",
+			formatSeconds(header.IdleTimeLimit))
+	}
+	if header.Theme.Fg != "" {
+		fmt.Fprintf(tw, "  theme fg:\t%s\n", header.Theme.Fg)
+	}
+	if header.Theme.Bg != "" {
+		fmt.Fprintf(tw, "  theme bg:\t%s\n", header.Theme.Bg)
+	}
+	if header.Theme.Palette != "" {
+		fmt.Fprintf(tw, "  theme palette:\t%s\n", header.Theme.Palette)
+	}
+	if header.Env.Shell != "" {
+		fmt.Fprintf(tw, "  env SHELL:\t%s\n", header.Env.Shell)
+	}
+	if header.Env.Term != "" {
+		fmt.Fprintf(tw, "  env TERM:\t%s\n", header.Env.Term)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "EVENTS")
+	fmt.Fprintf(tw, "  duration:\t%s\n", formatSeconds(summary.Duration))
+	fmt.Fprintf(tw, "  events:\t%d%s\n", summary.Events,
+		formatPerType(summary.EventsByType))
+	fmt.Fprintf(tw, "  bytes:\t%d%s\n", summary.Bytes,
+		formatPerType(summary.BytesByType))
+	if summary.TypedChars > 0 {
+		fmt.Fprintf(tw, "  typing speed:\t%.1f chars/s (%.0f wpm)\n",
+			summary.CharsPerSecond(), summary.WordsPerMinute())
+	}
+
+	if len(summary.LongestGaps) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "LONGEST GAPS")
+		for idx, gap := range summary.LongestGaps {
+			fmt.Fprintf(tw, "  %d.\t%s\tat %s (before event #%d)\n",
+				idx+1, formatSeconds(gap.Duration),
+				formatSeconds(gap.Time), gap.Index)
+		}
+	}
+
+	err = tw.Flush()
+	if err != nil {
+		return
+	}
+
+	if len(summary.Delays) == 0 {
+		return
+	}
+
+	buckets, err := stats.Histogram(summary.Delays, stats.DefaultHistogramBounds)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "DELAYS")
+	for _, p := range []float64{50, 90, 95, 99, 100} {
+		value, _ := stats.Percentile(summary.Delays, p)
+		fmt.Fprintf(tw, "  p%.0f:\t%s\n", p, formatSeconds(value))
+	}
+
+	fmt.Fprintln(tw)
+	writeHistogram(tw, buckets)
+
+	err = tw.Flush()
+	return
+}
+
+func writeHistogram(w io.Writer, buckets []stats.Bucket) {
+	var max int
+
+	for _, bucket := range buckets {
+		if bucket.Count > max {
+			max = bucket.Count
+		}
+	}
+
+	for _, bucket := range buckets {
+		var (
+			to   = "+inf"
+			size int
+		)
+
+		if bucket.To != math.MaxFloat64 {
+			to = formatSeconds(bucket.To)
+		}
+
+		if max > 0 {
+			size = int(math.Ceil(
+				float64(bucket.Count) / float64(max) * histogramWidth))
+		}
+
+		fmt.Fprintf(w, "  [%s, %s)\t%d\t%s\n",
+			formatSeconds(bucket.From), to, bucket.Count,
+			strings.Repeat("#", size))
+	}
+}
+
+// formatPerType formats a per-event-type count in a stable
+// order (e.g., " (i: 1, o: 2)").
+func formatPerType(values map[string]int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	var (
+		types = make([]string, 0, len(values))
+		parts = make([]string, 0, len(values))
+	)
+
+	for evType := range values {
+		types = append(types, evType)
+	}
+	sort.Strings(types)
+
+	for _, evType := range types {
+		parts = append(parts,
+			fmt.Sprintf("%s: %d", evType, values[evType]))
+	}
+
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// formatSeconds formats an amount of seconds in a human
+// readable way (e.g., 10ms, 1.5s).
+func formatSeconds(seconds float64) string {
+	if seconds != 0 && math.Abs(seconds) < 1 {
+		return fmt.Sprintf("%.4gms", seconds*1000)
+	}
+
+	return fmt.Sprintf("%.6gs", seconds)
+}
+
+// writeEvents lists the events of a cast, one per line, along with
+// their position in the event stream.
+func writeEvents(w io.Writer, c *cast.Cast) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "INDEX\tTIME\tTYPE\tDATA")
+
+	for idx, ev := range c.EventStream {
+		data := []rune(strconv.Quote(ev.Data))
+		if len(data) > eventDataWidth {
+			data = append(data[:eventDataWidth-3], []rune("...")...)
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n",
+			idx, strconv.FormatFloat(ev.Time, 'f', -1, 64), ev.Type,
+			string(data))
+	}
+
+	tw.Flush()
+}
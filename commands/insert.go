@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Insert = cli.Command{
+	Name: "insert",
+	Usage: `Inserts the events of another cast at a given time.
+
+   The events of the cast specified in '--cast' are spliced in at the
+   time specified in '--at', with all of the events that happen at or
+   after such time being delayed by the duration of the inserted cast.
+
+   When the terminal sizes differ, '--size' determines what to do:
+
+   - fail: abort the insertion (default);
+   - max: use the biggest width and height, letterboxing the smaller
+     contents; or
+   - resize: emit resize events before and after the inserted events.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Insert the re-recorded part "fix.cast" at 12.5s of "123.cast":
+
+     asciinema-edit insert \
+       --cast ./fix.cast \
+       --at 12.5 \
+       ./123.cast
+
+   Insert a cast recorded with a different terminal size, resizing
+   the terminal during playback:
+
+     asciinema-edit insert \
+       --cast ./fix.cast \
+       --at 12.5 \
+       --size resize \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    insertAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "cast",
+			Usage: "file containing the cast to insert (required)",
+		},
+		cli.Float64Flag{
+			Name:  "at",
+			Usage: "timestamp where the cast gets inserted",
+		},
+		cli.StringFlag{
+			Name:  "size",
+			Value: string(editor.SizeFail),
+			Usage: "size mismatch policy (fail, max or resize)",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type insertTransformation struct {
+	other  *cast.Cast
+	at     float64
+	policy editor.SizePolicy
+}
+
+func (t *insertTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.Insert(c, t.other, t.at, t.policy)
+	return
+}
+
+func insertAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		other          = c.String("cast")
+		transformation = &insertTransformation{
+			at: c.Float64("at"),
+		}
+	)
+
+	if other == "" {
+		err = cli.NewExitError("a cast to insert must be specified.", 1)
+		return
+	}
+
+	transformation.policy, err = editor.ParseSizePolicy(c.String("size"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	transformation.other, err = readCast(other)
+	if err != nil {
+		err = cli.NewExitError(errors.Wrapf(err,
+			"failed to read cast to insert"), 1)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// readCast decodes and validates a cast from the file named `input`,
+// falling back to stdin if no name is specified.
+func readCast(input string) (c *cast.Cast, err error) {
+	var file = os.Stdin
+
+	if input != "" {
+		file, err = os.Open(input)
+		if err != nil {
+			err = errors.Wrapf(err,
+				"failed to open input file %s", input)
+			return
+		}
+		defer file.Close()
+	}
+
+	c, err = cast.Decode(file)
+	if err != nil {
+		err = errors.Wrapf(err,
+			"failed to decode cast from input")
+		return
+	}
+
+	_, err = cast.Validate(c)
+	if err != nil {
+		err = errors.Wrapf(err,
+			"invalid input cast")
+		return
+	}
+
+	return
+}
+
+// writeCast encodes a cast into the file named `output`, falling back
+// to stdout if no name is specified.
+func writeCast(output string, c *cast.Cast) (err error) {
+	var file = os.Stdout
+
+	if output != "" {
+		file, err = os.Create(output)
+		if err != nil {
+			err = errors.Wrapf(err,
+				"failed to open output file %s", output)
+			return
+		}
+		defer file.Close()
+	}
+
+	err = cast.Encode(file, c)
+	if err != nil {
+		err = errors.Wrapf(err,
+			"failed to save cast")
+		return
+	}
+
+	return
+}
+
+// countingWriter discards everything written to it, keeping track of
+// the number of bytes.
+type countingWriter struct {
+	count int64
+}
+
+func (w *countingWriter) Write(p []byte) (n int, err error) {
+	w.count += int64(len(p))
+	n = len(p)
+	return
+}
+
+// encodedSize computes the number of bytes that a cast takes once
+// encoded.
+func encodedSize(c *cast.Cast) (size int64, err error) {
+	var writer countingWriter
+
+	err = cast.Encode(&writer, c)
+	if err != nil {
+		err = errors.Wrapf(err,
+			"failed to encode cast")
+		return
+	}
+
+	size = writer.count
+	return
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Lint = cli.Command{
+	Name:    "lint",
+	Aliases: []string{"validate"},
+	Usage: `Reports all the problems found in a cast.
+
+   Differently from the other commands (which stop at the first problem
+   found when reading a cast), every line of the cast gets checked,
+   with each problem being reported with its line number and severity:
+
+   - error: the cast is invalid (e.g., malformed lines, negative or
+     out-of-order timestamps, unknown event types and invalid UTF-8);
+     and
+   - warning: the cast is valid but probably won't play as expected
+     (e.g., suspiciously long gaps, incomplete escape sequences and
+     cursor movements outside of the terminal size).
+
+   The command exits with a non-zero status code if any errors are
+   found (or warnings, if '--strict' is set), making it suitable for
+   continuous integration.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+EXAMPLES:
+   Check the cast "123.cast":
+
+     asciinema-edit lint ./123.cast
+
+   Fail on any problem, considering gaps longer than 10s suspicious:
+
+     asciinema-edit lint --strict --max-gap 10 ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    lintAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "max-gap",
+			Value: cast.DefaultLintOptions.MaxGap,
+			Usage: "delay (in seconds) above which gaps are reported (0 disables)",
+		},
+		cli.BoolFlag{
+			Name:  "strict",
+			Usage: "exit with a non-zero status code on warnings too",
+		},
+	},
+}
+
+func lintAction(c *cli.Context) (err error) {
+	var (
+		input       = c.Args().First()
+		name        = input
+		file        = os.Stdin
+		diagnostics []cast.Diagnostic
+		options     = cast.LintOptions{
+			MaxGap: c.Float64("max-gap"),
+		}
+	)
+
+	if input != "" {
+		file, err = os.Open(input)
+		if err != nil {
+			err = cli.NewExitError(errors.Wrapf(err,
+				"failed to open input file %s", input), 1)
+			return
+		}
+		defer file.Close()
+	} else {
+		name = "<stdin>"
+	}
+
+	diagnostics, err = cast.Lint(file, options)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Printf("%s:%s\n", name, diagnostic)
+	}
+
+	if cast.HasErrors(diagnostics) {
+		err = cli.NewExitError("errors found", 1)
+		return
+	}
+
+	if c.Bool("strict") && len(diagnostics) > 0 {
+		err = cli.NewExitError("warnings found", 1)
+		return
+	}
+
+	return
+}
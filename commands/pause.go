@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Pause = cli.Command{
+	Name: "pause",
+	Usage: `Inserts pauses and holds the final frame of a cast.
+
+   A pause of '--duration' seconds is inserted right before each of the
+   timestamps specified in '--at' and before each of the markers whose
+   label is specified in '--marker', delaying all of the events that
+   follow. Timestamps refer to the original cast.
+
+   With '--hold', the final frame stays on the screen for that many
+   seconds (an output event that prints nothing is appended to carry
+   the time).
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Hold the screen for 5 seconds before 12.3s and before the marker
+   labeled "deploy":
+
+     asciinema-edit pause \
+       --duration 5 \
+       --at 12.3 \
+       --marker deploy \
+       ./123.cast
+
+   Keep the final frame on the screen for 10 seconds:
+
+     asciinema-edit pause --hold 10 ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    pauseAction,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "at",
+			Usage: "timestamp to pause before",
+		},
+		cli.StringSliceFlag{
+			Name:  "marker",
+			Usage: "label of the marker to pause before",
+		},
+		cli.Float64Flag{
+			Name:  "duration",
+			Usage: "duration (in seconds) of each pause",
+		},
+		cli.Float64Flag{
+			Name:  "hold",
+			Usage: "time (in seconds) to hold the final frame for",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type pauseTransformation struct {
+	at       []float64
+	markers  []string
+	duration float64
+	hold     float64
+}
+
+func (t *pauseTransformation) Transform(c *cast.Cast) (err error) {
+	var points = t.at
+
+	for _, label := range t.markers {
+		var point float64
+
+		point, err = editor.MarkerTime(c, label)
+		if err != nil {
+			return
+		}
+
+		points = append(points, point)
+	}
+
+	if len(points) != 0 {
+		err = editor.Pause(c, points, t.duration)
+		if err != nil {
+			return
+		}
+	}
+
+	if t.hold != 0 {
+		err = editor.Hold(c, t.hold)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func pauseAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &pauseTransformation{
+			markers:  c.StringSlice("marker"),
+			duration: c.Float64("duration"),
+			hold:     c.Float64("hold"),
+		}
+	)
+
+	transformation.at, err = parseTimestamps(c.StringSlice("at"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if len(transformation.at) == 0 && len(transformation.markers) == 0 &&
+		transformation.hold == 0 {
+		err = cli.NewExitError(
+			"a pause (--at or --marker) or a hold must be specified.", 1)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
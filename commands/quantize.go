@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"fmt"
+	"io"
 	"math"
+	"os"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/cirocosta/asciinema-edit/cast"
 	"github.com/cirocosta/asciinema-edit/commands/transformer"
@@ -37,15 +41,40 @@ var Quantize = cli.Command{
       delta = 1.000000 | qdelta = 1.000000
       delta = 1.000000 | qdelta = 1.000000
 
+   Instead of guessing the ranges, they can be derived from the delays
+   of the cast itself: '--percentile 95' caps every delay longer than
+   the 95th percentile of all delays to such value, while '--max-idle 2'
+   caps every delay longer than 2 seconds. When both are specified, the
+   smallest cap is used. The chosen range is reported to stderr.
+
+   Quantization can be restricted to the delays within time windows
+   ('--window start,end', in the timestamps of the original cast),
+   between the events at the positions from '--from-index' to
+   '--to-index', within the sections that start at markers ('--marker
+   label') or around the events whose data matches an expression
+   ('--match regexp', padded with '--before' and '--after' seconds), as
+   well as to the delays that precede events of certain types ('--type
+   i' for the typing cadence, '--type o' for program output), keeping
+   any other delay intact.
+
+   Ranges must not overlap (e.g., '--range 1,3 --range 2,4'), unless
+   '--overlap merge' is specified, in which case overlapping ranges are
+   joined (quantizing to the lowest value of the joined ranges). Delays
+   that lie between ranges are left untouched, which gets noted in
+   stderr.
+
+   With '--explain', a table showing which range each delay fell into
+   is written to stderr.
+
    If no file name is specified as a positional argument, a cast is
-   expected to be serverd via stdin.
+   expected to be served via stdin.
 
    Once the transformation has been performed, the resulting cast is
    either written to a file specified in the '--out' flag or to stdout
    (default).
 
 EXAMPLES:
-   Make the whole cast have a maximum delay of 1s:
+   Make the whole cast have a maximum delay of 2s:
 
      asciinema-edit quantize --range 2 ./123.cast
 
@@ -57,30 +86,129 @@ EXAMPLES:
        --range 0.3,1 \
        --range 1,2 \
        --range 2 \
+       ./123.cast
+
+   Cap the 5% longest delays to the 95th percentile of the delays:
+
+     asciinema-edit quantize --percentile 95 ./123.cast
+
+   Tighten the typing pauses of the first minute, keeping the pauses
+   before program output intact:
+
+     asciinema-edit quantize \
+       --range 0.5 \
+       --type i \
+       --window 0,60 \
        ./123.cast`,
 	ArgsUsage: "[filename]",
 	Action:    quantizeAction,
-	Flags: []cli.Flag{
+	Flags: withSelection([]cli.Flag{
 		cli.StringSliceFlag{
 			Name:  "range",
 			Usage: "quantization ranges (comma delimited)",
 		},
+		cli.Float64Flag{
+			Name:  "percentile",
+			Usage: "cap delays at this percentile of all delays (0-100)",
+		},
+		cli.Float64Flag{
+			Name:  "max-idle",
+			Usage: "cap delays at this many seconds",
+		},
 		cli.StringFlag{
-			Name:  "out",
-			Usage: "file to write the modified contents to",
+			Name:  "overlap",
+			Value: string(editor.OverlapFail),
+			Usage: "overlapping ranges policy (fail or merge)",
+		},
+		cli.BoolFlag{
+			Name:  "explain",
+			Usage: "report which range each delay fell into",
 		},
-	},
+	}, cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the modified contents to",
+	}),
 }
 
 type quantizeTransformation struct {
-	ranges []editor.QuantizeRange
+	ranges    []editor.QuantizeRange
+	adaptive  *editor.AdaptiveOptions
+	selection editor.Selection
+	policy    editor.OverlapPolicy
+	explain   bool
 }
 
 func (t *quantizeTransformation) Transform(c *cast.Cast) (err error) {
-	err = editor.Quantize(c, t.ranges)
+	var (
+		ranges    = t.ranges
+		decisions []editor.QuantizeDecision
+	)
+
+	if t.adaptive != nil {
+		var qRange editor.QuantizeRange
+
+		qRange, err = editor.AdaptiveRange(c, *t.adaptive)
+		if err != nil {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "chose range %g: delays longer than %s "+
+			"are capped\n", qRange.From, formatSeconds(qRange.From))
+
+		ranges = append(ranges, qRange)
+	}
+
+	ranges, err = editor.NormalizeQuantizeRanges(ranges, t.policy)
+	if err != nil {
+		return
+	}
+
+	for _, gap := range editor.QuantizeGaps(ranges) {
+		fmt.Fprintf(os.Stderr, "note: delays within %s are not quantized\n",
+			gap)
+	}
+
+	if t.explain {
+		decisions, err = editor.ExplainQuantize(c, ranges, t.selection)
+		if err != nil {
+			return
+		}
+
+		writeExplanation(os.Stderr, decisions)
+	}
+
+	err = editor.QuantizeScoped(c, ranges, t.selection)
 	return
 }
 
+// writeExplanation writes a table describing what happens to each
+// delay of a cast when quantizing.
+func writeExplanation(w io.Writer, decisions []editor.QuantizeDecision) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "EVENT\tTIME\tDELAY\tRANGE\tQUANTIZED")
+
+	for _, decision := range decisions {
+		rangeDesc := "-"
+
+		switch {
+		case !decision.InScope:
+			rangeDesc = "out of scope"
+		case decision.Range != nil:
+			rangeDesc = decision.Range.String()
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+			decision.Index,
+			formatSeconds(decision.Time),
+			formatSeconds(decision.Delay),
+			rangeDesc,
+			formatSeconds(decision.Quantized))
+	}
+
+	tw.Flush()
+}
+
 // ParseQuantizeRange takes an input string that represents
 // a quantization range and converts it into a QuantizeRange
 // instance.
@@ -163,14 +291,35 @@ func quantizeAction(c *cli.Context) (err error) {
 		input          = c.Args().First()
 		output         = c.String("out")
 		ranges         = c.StringSlice("range")
-		transformation = &quantizeTransformation{}
+		transformation = &quantizeTransformation{
+			explain: c.Bool("explain"),
+		}
 	)
 
-	if len(ranges) == 0 {
+	if c.IsSet("percentile") || c.IsSet("max-idle") {
+		transformation.adaptive = &editor.AdaptiveOptions{
+			Percentile: c.Float64("percentile"),
+			MaxIdle:    c.Float64("max-idle"),
+		}
+	}
+
+	if len(ranges) == 0 && transformation.adaptive == nil {
 		err = cli.NewExitError("a range must be specified.", 1)
 		return
 	}
 
+	transformation.policy, err = editor.ParseOverlapPolicy(c.String("overlap"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	transformation.selection, _, err = parseSelection(c)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
 	transformation.ranges, err = parseQuantizeRanges(ranges)
 	if err != nil {
 		err = cli.NewExitError(err, 1)
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Repeat = cli.Command{
+	Name: "repeat",
+	Usage: `Repeats a segment of a cast a number of times.
+
+   The events from '--start' (included) to '--end' (excluded) are
+   played '--times' times in a row, with the events that follow the
+   segment being delayed accordingly.
+
+   So that each repetition starts from the same terminal state as the
+   original segment, if the segment doesn't leave the screen as it
+   found it, every copy is preceded by an output event that resets the
+   terminal and replays the output that preceded the segment since the
+   last time the screen got cleared.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Show the dashboard refreshing (from 10s to 15s) 5 times:
+
+     asciinema-edit repeat \
+       --start 10 \
+       --end 15 \
+       --times 5 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    repeatAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "start",
+			Usage: "initial timestamp of the segment",
+		},
+		cli.Float64Flag{
+			Name:  "end",
+			Usage: "final timestamp of the segment (excluded)",
+		},
+		cli.IntFlag{
+			Name:  "times",
+			Value: 2,
+			Usage: "number of times the segment is played",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type repeatTransformation struct {
+	from  float64
+	to    float64
+	times int
+}
+
+func (t *repeatTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.Repeat(c, t.from, t.to, t.times)
+	return
+}
+
+func repeatAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &repeatTransformation{
+			from:  c.Float64("start"),
+			to:    c.Float64("end"),
+			times: c.Int("times"),
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
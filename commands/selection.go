@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"regexp"
+
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// selectionFlags are the flags that select the events that a command
+// acts on (see `parseSelection`).
+var selectionFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "window",
+		Usage: "select the events within a time range (start,end)",
+	},
+	cli.IntFlag{
+		Name:  "from-index",
+		Usage: "position of the initial event (see 'info --events')",
+	},
+	cli.IntFlag{
+		Name:  "to-index",
+		Usage: "position of the final event (see 'info --events')",
+	},
+	cli.StringSliceFlag{
+		Name:  "marker",
+		Usage: "select the section that starts at the marker with this label",
+	},
+	cli.StringFlag{
+		Name:  "match",
+		Usage: "select the events whose data matches this regular expression",
+	},
+	cli.Float64Flag{
+		Name:  "before",
+		Usage: "seconds to select before each event matched by --match",
+	},
+	cli.Float64Flag{
+		Name:  "after",
+		Usage: "seconds to select after each event matched by --match",
+	},
+	cli.StringSliceFlag{
+		Name:  "type",
+		Usage: "select only the events of this type",
+	},
+}
+
+// parseSelection retrieves the selection specified via the selection
+// flags, indicating whether any of them was specified.
+//
+// Fails if the flags are malformed or if they're used together with
+// the timestamp flags ('--start' and '--end').
+func parseSelection(c *cli.Context) (selection editor.Selection, selected bool, err error) {
+	for _, flag := range selectionFlags {
+		if c.IsSet(flag.GetName()) {
+			selected = true
+			break
+		}
+	}
+
+	if !selected {
+		return
+	}
+
+	if c.IsSet("start") || c.IsSet("end") {
+		err = errors.Errorf(
+			"selections can't be used together with --start or --end")
+		return
+	}
+
+	for _, input := range c.StringSlice("window") {
+		var window editor.TimeRange
+
+		window, err = ParseTimeRange(input)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse window %s", input)
+			return
+		}
+
+		selection.Times = append(selection.Times, window)
+	}
+
+	if c.IsSet("from-index") || c.IsSet("to-index") {
+		if !c.IsSet("from-index") || !c.IsSet("to-index") {
+			err = errors.Errorf(
+				"both --from-index and --to-index must be specified")
+			return
+		}
+
+		selection.Indexes = append(selection.Indexes, editor.IndexRange{
+			From: c.Int("from-index"),
+			To:   c.Int("to-index"),
+		})
+	}
+
+	if c.IsSet("before") || c.IsSet("after") {
+		if !c.IsSet("match") {
+			err = errors.Errorf(
+				"--before and --after can only be used with --match")
+			return
+		}
+	}
+
+	if c.IsSet("match") {
+		selection.Match, err = regexp.Compile(c.String("match"))
+		if err != nil {
+			err = errors.Wrapf(err, "malformed expression %s",
+				c.String("match"))
+			return
+		}
+
+		selection.Before = c.Float64("before")
+		selection.After = c.Float64("after")
+	}
+
+	selection.Markers = c.StringSlice("marker")
+	selection.Types = c.StringSlice("type")
+	return
+}
+
+// withSelection builds the flags of a command, placing the selection
+// flags between `flags` and `rest`.
+func withSelection(flags []cli.Flag, rest ...cli.Flag) (res []cli.Flag) {
+	res = make([]cli.Flag, 0, len(flags)+len(selectionFlags)+len(rest))
+	res = append(res, flags...)
+	res = append(res, selectionFlags...)
+	res = append(res, rest...)
+	return
+}
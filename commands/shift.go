@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"math"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Shift = cli.Command{
+	Name: "shift",
+	Usage: `Offsets the timestamps of the events of a cast.
+
+   The events that lie between '--start' and '--end' (both included)
+   are moved by the duration specified in '--by' (e.g., '2.5s' or
+   '-500ms'). The shift must neither make timestamps negative nor
+   change the order of the events.
+
+   If no range is specified (start=0, end=0), the whole event stream
+   is shifted.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Delay the whole cast by 2.5 seconds:
+
+     asciinema-edit shift --by 2.5s ./123.cast
+
+   Bring the events from 10s to 12s half a second closer to the
+   events that precede them:
+
+     asciinema-edit shift \
+       --by -500ms \
+       --start 10 \
+       --end 12 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    shiftAction,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "by",
+			Usage: "amount of time to shift the events by",
+		},
+		cli.Float64Flag{
+			Name:  "start",
+			Usage: "initial timestamp",
+		},
+		cli.Float64Flag{
+			Name:  "end",
+			Usage: "final timestamp",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+var Rebase = cli.Command{
+	Name: "rebase",
+	Usage: `Makes the first event of a cast happen at 0.
+
+   All of the events are shifted back by the timestamp of the first
+   event, removing the idle time at the beginning of the cast.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Rebase the cast "123.cast":
+
+     asciinema-edit rebase ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    rebaseAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type shiftTransformation struct {
+	by   float64
+	from float64
+	to   float64
+}
+
+func (t *shiftTransformation) Transform(c *cast.Cast) (err error) {
+	if t.from == 0 && t.to == 0 {
+		t.to = math.MaxFloat64
+	}
+
+	err = editor.Shift(c, t.by, t.from, t.to)
+	return
+}
+
+type rebaseTransformation struct{}
+
+func (t *rebaseTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.Rebase(c)
+	return
+}
+
+func shiftAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &shiftTransformation{
+			by:   c.Duration("by").Seconds(),
+			from: c.Float64("start"),
+			to:   c.Float64("end"),
+		}
+	)
+
+	if transformation.by == 0 {
+		err = cli.NewExitError("a non-zero shift (--by) must be specified.", 1)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
+
+func rebaseAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &rebaseTransformation{}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/cirocosta/asciinema-edit/cast"
 	"github.com/cirocosta/asciinema-edit/commands/transformer"
 	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -12,11 +16,24 @@ var Speed = cli.Command{
 	Usage: `Updates the cast speed by a certain factor.
 
    If no file name is specified as a positional argument, a cast is
-   expected to be serverd via stdin.
+   expected to be served via stdin.
 
    If no range is specified (start=0, end=0), the whole event stream
    is processed.
 
+   Instead of timestamps, the delays to update can be selected by time
+   ('--window start,end'), by the position of the events around them
+   ('--from-index' and '--to-index'), by the sections that start at
+   markers ('--marker label'), by the data of the events around them
+   ('--match regexp', padded with '--before' and '--after' seconds) or
+   by the type of the event that follows them ('--type i').
+
+   Multiple segments can be processed in a single pass by repeating
+   '--range start,end,factor'. All ranges refer to the timestamps of
+   the original cast, thus they're not affected by each other.
+
+   Factors must lie between '--min-factor' and '--max-factor'.
+
    Once the transformation has been performed, the resulting cast is
    either written to a file specified in the '--out' flag or to stdout
    (default).
@@ -35,11 +52,29 @@ EXAMPLES:
      asciinema-edit speed \
         --factor 2 \
         --start 12.231 \
-        --factor 45.333 \
+        --end 45.333 \
+        ./123.cast
+
+   Speed up two sections (4x and 2x) while slowing down another one:
+
+     asciinema-edit speed \
+        --range 12.231,45.333,0.25 \
+        --range 50.1,62.7,0.5 \
+        --range 70.2,75.9,2 \
+        ./123.cast
+
+   Speed up every section where 'npm install' prints its progress
+   (consecutive events mentioning 'idealTree', 'reify' or 'timing'),
+   as well as the second that precedes it:
+
+     asciinema-edit speed \
+        --factor 0.2 \
+        --match 'idealTree|reify|timing' \
+        --before 1 \
         ./123.cast`,
 	ArgsUsage: "[filename]",
 	Action:    speedAction,
-	Flags: []cli.Flag{
+	Flags: withSelection([]cli.Flag{
 		cli.Float64Flag{
 			Name:  "factor",
 			Usage: "number by which delays are multiplied by",
@@ -52,26 +87,84 @@ EXAMPLES:
 			Name:  "end",
 			Usage: "final frame timestamp",
 		},
-		cli.StringFlag{
-			Name:  "out",
-			Usage: "file to write the modified contents to",
+		cli.StringSliceFlag{
+			Name:  "range",
+			Usage: "speed ranges (start,end,factor)",
+		},
+		cli.Float64Flag{
+			Name:  "min-factor",
+			Value: editor.DefaultFactorBounds.Min,
+			Usage: "minimum factor allowed",
 		},
-	},
+		cli.Float64Flag{
+			Name:  "max-factor",
+			Value: editor.DefaultFactorBounds.Max,
+			Usage: "maximum factor allowed",
+		},
+	}, cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the modified contents to",
+	}),
 }
 
 type speedTransformation struct {
-	from   float64
-	to     float64
-	factor float64
+	ranges    []editor.SpeedRange
+	factor    float64
+	selection *editor.Selection
+	bounds    editor.FactorBounds
 }
 
 func (t *speedTransformation) Transform(c *cast.Cast) (err error) {
-	if t.from == 0 && t.to == 0 {
-		t.from = c.EventStream[0].Time
-		t.to = c.EventStream[len(c.EventStream)-1].Time
+	if t.selection != nil {
+		err = editor.SpeedSelection(c, t.factor, *t.selection, t.bounds)
+		return
+	}
+
+	for idx := range t.ranges {
+		if t.ranges[idx].From == 0 && t.ranges[idx].To == 0 {
+			t.ranges[idx].From = c.EventStream[0].Time
+			t.ranges[idx].To = c.EventStream[len(c.EventStream)-1].Time
+		}
+	}
+
+	err = editor.SpeedRanges(c, t.ranges, t.bounds)
+	return
+}
+
+// ParseSpeedRange takes an input string that represents
+// a speed range and converts it into a SpeedRange instance.
+//
+// The format is `start,end,factor` (e.g., `1.2,5.3,0.5`).
+//
+// Fails if the input can't be converted to a SpeedRange.
+func ParseSpeedRange(input string) (res editor.SpeedRange, err error) {
+	cols := strings.Split(input, ",")
+
+	if len(cols) != 3 {
+		err = errors.Errorf(
+			"invalid range format: must be `start,end,factor`")
+		return
+	}
+
+	values := make([]float64, len(cols))
+	for idx, col := range cols {
+		values[idx], err = strconv.ParseFloat(col, 64)
+		if err != nil {
+			err = errors.Errorf(
+				"malformed range: element %d is not a float '%s'",
+				idx+1, col)
+			return
+		}
+	}
+
+	res.From, res.To, res.Factor = values[0], values[1], values[2]
+
+	if res.From >= res.To {
+		err = errors.Errorf(
+			"constraint not verified: start < end")
+		return
 	}
 
-	err = editor.Speed(c, t.factor, t.from, t.to)
 	return
 }
 
@@ -80,12 +173,51 @@ func speedAction(c *cli.Context) (err error) {
 		input          = c.Args().First()
 		output         = c.String("out")
 		transformation = &speedTransformation{
-			factor: c.Float64("factor"),
-			from:   c.Float64("start"),
-			to:     c.Float64("end"),
+			ranges: make([]editor.SpeedRange, 0),
+			bounds: editor.FactorBounds{
+				Min: c.Float64("min-factor"),
+				Max: c.Float64("max-factor"),
+			},
 		}
 	)
 
+	for _, input := range c.StringSlice("range") {
+		var sRange editor.SpeedRange
+
+		sRange, err = ParseSpeedRange(input)
+		if err != nil {
+			err = cli.NewExitError(errors.Wrapf(err,
+				"failed to parse range %s", input), 1)
+			return
+		}
+
+		transformation.ranges = append(transformation.ranges, sRange)
+	}
+
+	selection, selected, err := parseSelection(c)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if selected {
+		if len(transformation.ranges) != 0 {
+			err = cli.NewExitError(
+				"selections can't be used together with --range.", 1)
+			return
+		}
+
+		transformation.selection = &selection
+		transformation.factor = c.Float64("factor")
+	} else if len(transformation.ranges) == 0 || c.IsSet("factor") {
+		transformation.ranges = append(transformation.ranges,
+			editor.SpeedRange{
+				Factor: c.Float64("factor"),
+				From:   c.Float64("start"),
+				To:     c.Float64("end"),
+			})
+	}
+
 	t, err := transformer.New(transformation, input, output)
 	if err != nil {
 		err = cli.NewExitError(err, 1)
@@ -0,0 +1,64 @@
+package commands_test
+
+import (
+	"github.com/cirocosta/asciinema-edit/commands"
+	"github.com/cirocosta/asciinema-edit/editor"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseSpeedRange", func() {
+	Context("having empty input", func() {
+		It("fails", func() {
+			_, err := commands.ParseSpeedRange("")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having the wrong number of elements", func() {
+		It("fails with two elements", func() {
+			_, err := commands.ParseSpeedRange("1,2")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails with four elements", func() {
+			_, err := commands.ParseSpeedRange("1,2,3,4")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having invalid chars", func() {
+		It("fails if non-numeric", func() {
+			_, err := commands.ParseSpeedRange("1,a,2")
+			Expect(err).NotTo(Succeed())
+		})
+
+		It("fails with trailing comma", func() {
+			_, err := commands.ParseSpeedRange("1,2,")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having `start >= end`", func() {
+		It("fails", func() {
+			_, err := commands.ParseSpeedRange("2,2,1")
+			Expect(err).NotTo(Succeed())
+
+			_, err = commands.ParseSpeedRange("3,2,1")
+			Expect(err).NotTo(Succeed())
+		})
+	})
+
+	Context("having a valid entry", func() {
+		It("parses start, end and factor", func() {
+			sRange, err := commands.ParseSpeedRange("1.5,3,0.5")
+			Expect(err).To(Succeed())
+			Expect(sRange).To(Equal(editor.SpeedRange{
+				From:   1.5,
+				To:     3,
+				Factor: 0.5,
+			}))
+		})
+	})
+})
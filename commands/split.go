@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Split = cli.Command{
+	Name: "split",
+	Usage: `Splits a cast into multiple files.
+
+   The cast gets cut at the timestamps specified in '--at', at every
+   marker event (with '--markers') or at every interval specified in
+   '--every' (e.g., '5m'), with each part being written to a separate
+   file named after '--prefix' (e.g., 'name-01.cast', 'name-02.cast').
+
+   Each part gets re-based to start at 0 and, so that it renders
+   correctly, prefixed with an event that replays the output that
+   preceded it since the last time the screen got cleared.
+
+   If no prefix is specified, the name of the input file (without its
+   extension) is used.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+EXAMPLES:
+   Split the cast "123.cast" at 60s and 120s, creating "123-01.cast",
+   "123-02.cast" and "123-03.cast":
+
+     asciinema-edit split --at 60 --at 120 ./123.cast
+
+   Split a long training session into chapters delimited by markers:
+
+     asciinema-edit split \
+       --markers \
+       --prefix ./chapters/training \
+       ./training.cast
+
+   Split the cast every 5 minutes:
+
+     asciinema-edit split --every 5m ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    splitAction,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "at",
+			Usage: "timestamp to cut the cast at",
+		},
+		cli.BoolFlag{
+			Name:  "markers",
+			Usage: "cut the cast at every marker event",
+		},
+		cli.DurationFlag{
+			Name:  "every",
+			Usage: "cut the cast at every interval",
+		},
+		cli.StringFlag{
+			Name:  "prefix",
+			Usage: "prefix of the names of the files to write the parts to",
+		},
+	},
+}
+
+func splitAction(c *cli.Context) (err error) {
+	var (
+		input  = c.Args().First()
+		prefix = c.String("prefix")
+		every  = c.Duration("every")
+		points []float64
+		data   *cast.Cast
+		parts  []*cast.Cast
+	)
+
+	if prefix == "" {
+		if input == "" {
+			err = cli.NewExitError(
+				"a prefix must be specified when reading from stdin.", 1)
+			return
+		}
+
+		prefix = strings.TrimSuffix(input, filepath.Ext(input))
+	}
+
+	points, err = parseTimestamps(c.StringSlice("at"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	data, err = readCast(input)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	if c.Bool("markers") {
+		points = append(points, editor.MarkerTimes(data)...)
+	}
+
+	if every != 0 {
+		var times []float64
+
+		times, err = editor.IntervalTimes(data, every.Seconds())
+		if err != nil {
+			err = cli.NewExitError(err, 1)
+			return
+		}
+
+		points = append(points, times...)
+	}
+
+	if len(points) == 0 {
+		err = cli.NewExitError(
+			"cut points must be specified (--at, --markers or --every).", 1)
+		return
+	}
+
+	parts, err = editor.Split(data, points)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	for idx, part := range parts {
+		name := fmt.Sprintf("%s-%02d.cast", prefix, idx+1)
+
+		err = writeCast(name, part)
+		if err != nil {
+			err = cli.NewExitError(err, 1)
+			return
+		}
+
+		fmt.Println(name)
+	}
+
+	return
+}
+
+// parseTimestamps converts a list of strings into timestamps (in
+// seconds).
+func parseTimestamps(inputs []string) (timestamps []float64, err error) {
+	timestamps = make([]float64, 0, len(inputs))
+
+	for _, input := range inputs {
+		var timestamp float64
+
+		timestamp, err = strconv.ParseFloat(input, 64)
+		if err != nil {
+			err = errors.Errorf(
+				"malformed timestamp: '%s' is not a float", input)
+			return
+		}
+
+		timestamps = append(timestamps, timestamp)
+	}
+
+	return
+}
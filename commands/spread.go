@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Spread = cli.Command{
+	Name: "spread",
+	Usage: `Breaks large output bursts into chunks spread over time.
+
+   Output events that would be broken into at least '--threshold'
+   chunks are replaced by one event per chunk, evenly spread over
+   '--duration' seconds, with the events that follow being delayed by
+   the same amount. The size of the chunks is determined by '--mode':
+
+   - lines: one line per chunk, making output scroll (default); or
+   - chars: one character per chunk, making output look typed.
+
+   Escape sequences and multi-byte characters are never broken apart.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Make every output of 10 lines or more scroll over 2 seconds:
+
+     asciinema-edit spread --duration 2 ./123.cast
+
+   Type out every output of 20 characters or more over a second:
+
+     asciinema-edit spread \
+       --mode chars \
+       --threshold 20 \
+       --duration 1 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    spreadAction,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "mode",
+			Value: string(editor.ChunkLines),
+			Usage: "size of the chunks (lines or chars)",
+		},
+		cli.IntFlag{
+			Name:  "threshold",
+			Value: 10,
+			Usage: "minimum number of chunks for an event to be spread",
+		},
+		cli.Float64Flag{
+			Name:  "duration",
+			Value: 1,
+			Usage: "time (in seconds) over which the chunks are spread",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type spreadTransformation struct {
+	options editor.SpreadOptions
+	spread  int
+}
+
+func (t *spreadTransformation) Transform(c *cast.Cast) (err error) {
+	t.spread, err = editor.Spread(c, t.options)
+	return
+}
+
+func spreadAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &spreadTransformation{
+			options: editor.SpreadOptions{
+				Threshold: c.Int("threshold"),
+				Duration:  c.Float64("duration"),
+			},
+		}
+	)
+
+	transformation.options.Mode, err = editor.ParseChunkMode(c.String("mode"))
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "spread %d event(s)\n", transformation.spread)
+
+	return
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"math"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Strip = cli.Command{
+	Name: "strip",
+	Usage: `Removes events of certain types.
+
+   Differently from 'cut', the timing of the remaining events is kept
+   intact - only the events of the types specified in '--type' are
+   removed (input events, 'i', if none is specified).
+
+   This is specially useful for removing the keystrokes (which might
+   include typed passwords) captured by 'asciinema rec --stdin'.
+
+   The range goes from '--start' (the beginning of the cast, if not
+   specified) to '--end' (the end of the cast, if not specified), thus
+   the whole event stream is processed if none is specified.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Remove all the input events from "123.cast":
+
+     asciinema-edit strip ./123.cast
+
+   Remove input and marker events between 12.2s and 15.3s:
+
+     asciinema-edit strip \
+       --type i \
+       --type m \
+       --start 12.2 \
+       --end 15.3 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    stripAction,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "type",
+			Usage: "type of the events to remove (default: i)",
+		},
+		cli.Float64Flag{
+			Name:  "start",
+			Usage: "initial timestamp",
+		},
+		cli.Float64Flag{
+			Name:  "end",
+			Usage: "final timestamp",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type stripTransformation struct {
+	types []string
+	from  float64
+	to    float64
+}
+
+func (t *stripTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.Strip(c, t.types, t.from, t.to)
+	return
+}
+
+func stripAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &stripTransformation{
+			types: c.StringSlice("type"),
+			from:  c.Float64("start"),
+			to:    math.MaxFloat64,
+		}
+	)
+
+	if c.IsSet("end") {
+		transformation.to = c.Float64("end")
+	}
+
+	if len(transformation.types) == 0 {
+		transformation.types = []string{"i"}
+	}
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
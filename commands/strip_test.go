@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands"
+	"gopkg.in/urfave/cli.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Strip", func() {
+	var (
+		dir    string
+		input  string
+		output string
+	)
+
+	const contents = `{"version": 2, "width": 80, "height": 24}
+[1, "i", "a"]
+[2, "o", "a"]
+[5, "i", "b"]
+[6, "o", "b"]
+[8, "i", "c"]
+`
+
+	run := func(args ...string) (err error) {
+		app := cli.NewApp()
+		app.Commands = []cli.Command{commands.Strip}
+
+		err = app.Run(append(append([]string{"asciinema-edit", "strip"},
+			args...), "--out", output, input))
+		return
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "strip")
+		Expect(err).To(Succeed())
+
+		input = filepath.Join(dir, "input.cast")
+		output = filepath.Join(dir, "output.cast")
+
+		err = ioutil.WriteFile(input, []byte(contents), 0644)
+		Expect(err).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	events := func() (res []string) {
+		file, err := os.Open(output)
+		Expect(err).To(Succeed())
+		defer file.Close()
+
+		c, err := cast.Decode(file)
+		Expect(err).To(Succeed())
+
+		for _, ev := range c.EventStream {
+			res = append(res, ev.Type+ev.Data)
+		}
+		return
+	}
+
+	It("strips up to the end of the cast without --end", func() {
+		Expect(run("--start", "5")).To(Succeed())
+		Expect(strings.Join(events(), " ")).To(Equal("ia oa ob"))
+	})
+
+	It("strips from the beginning of the cast without --start", func() {
+		Expect(run("--end", "5")).To(Succeed())
+		Expect(strings.Join(events(), " ")).To(Equal("oa ob ic"))
+	})
+
+	It("strips the whole cast without a range", func() {
+		Expect(run()).To(Succeed())
+		Expect(strings.Join(events(), " ")).To(Equal("oa ob"))
+	})
+})
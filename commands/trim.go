@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Trim = cli.Command{
+	Name: "trim",
+	Usage: `Removes idle time from the beginning and the end of a cast.
+
+   Leading idle time is the time before the first visible output (i.e.,
+   output that prints something other than whitespace and escape
+   sequences), while trailing idle time is the time after the last one.
+
+   Events that happen before the first visible output (e.g., the
+   terminal title being set) are kept, with the cast being re-based to
+   start at 0.
+
+   With '--remove-exit', the final 'exit' command (and everything that
+   follows it) is removed before trimming.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Remove all the idle time from both ends of "123.cast":
+
+     asciinema-edit trim ./123.cast
+
+   Keep half a second of idle time at both ends, removing the final
+   exit command:
+
+     asciinema-edit trim \
+       --threshold 0.5 \
+       --remove-exit \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    trimAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "threshold",
+			Usage: "idle time (in seconds) to keep at both ends",
+		},
+		cli.BoolFlag{
+			Name:  "remove-exit",
+			Usage: "remove the final exit command",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type trimTransformation struct {
+	options editor.TrimOptions
+}
+
+func (t *trimTransformation) Transform(c *cast.Cast) (err error) {
+	err = editor.Trim(c, t.options)
+	return
+}
+
+func trimAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &trimTransformation{
+			options: editor.TrimOptions{
+				Threshold:  c.Float64("threshold"),
+				RemoveExit: c.Bool("remove-exit"),
+			},
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	return
+}
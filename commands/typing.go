@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/commands/transformer"
+	"github.com/cirocosta/asciinema-edit/editor"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var Humanize = cli.Command{
+	Name: "humanize",
+	Usage: `Makes the typing cadence look human.
+
+   Typing streaks (keystrokes echoed one character at a time, without
+   pausing) get their keystrokes redistributed with a random variation
+   of up to '--jitter' times the average delay of the streak, keeping
+   the duration of each streak.
+
+   The variation is computed from '--seed', thus the same seed always
+   leads to the same result.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Make the delays between keystrokes vary up to 40%:
+
+     asciinema-edit humanize ./123.cast
+
+   Use a stronger variation, with another seed:
+
+     asciinema-edit humanize \
+       --jitter 0.7 \
+       --seed 1234 \
+       ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    humanizeAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "jitter",
+			Value: 0.4,
+			Usage: "maximum variation of the delays (0 to 1)",
+		},
+		cli.Int64Flag{
+			Name:  "seed",
+			Usage: "seed of the random variation",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+var NormalizeTyping = cli.Command{
+	Name: "normalize-typing",
+	Usage: `Makes all typing happen at a fixed rate.
+
+   Typing streaks (keystrokes echoed one character at a time, without
+   pausing) get their keystrokes evenly spaced at '--cps' characters
+   per second, with the events that follow each streak being moved
+   accordingly.
+
+   If no file name is specified as a positional argument, a cast is
+   expected to be served via stdin.
+
+   Once the transformation has been performed, the resulting cast is
+   either written to a file specified in the '--out' flag or to stdout
+   (default).
+
+EXAMPLES:
+   Type everything at 15 characters per second:
+
+     asciinema-edit normalize-typing --cps 15 ./123.cast`,
+	ArgsUsage: "[filename]",
+	Action:    normalizeTypingAction,
+	Flags: []cli.Flag{
+		cli.Float64Flag{
+			Name:  "cps",
+			Value: 10,
+			Usage: "typing rate (characters per second)",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "file to write the modified contents to",
+		},
+	},
+}
+
+type humanizeTransformation struct {
+	options editor.HumanizeOptions
+	streaks int
+}
+
+func (t *humanizeTransformation) Transform(c *cast.Cast) (err error) {
+	t.streaks, err = editor.Humanize(c, t.options)
+	return
+}
+
+type normalizeTypingTransformation struct {
+	cps     float64
+	streaks int
+}
+
+func (t *normalizeTypingTransformation) Transform(c *cast.Cast) (err error) {
+	t.streaks, err = editor.NormalizeTyping(c, t.cps)
+	return
+}
+
+func humanizeAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &humanizeTransformation{
+			options: editor.HumanizeOptions{
+				Jitter: c.Float64("jitter"),
+				Seed:   c.Int64("seed"),
+			},
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "retimed %d typing streak(s)\n",
+		transformation.streaks)
+
+	return
+}
+
+func normalizeTypingAction(c *cli.Context) (err error) {
+	var (
+		input          = c.Args().First()
+		output         = c.String("out")
+		transformation = &normalizeTypingTransformation{
+			cps: c.Float64("cps"),
+		}
+	)
+
+	t, err := transformer.New(transformation, input, output)
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+	defer t.Close()
+
+	err = t.Transform()
+	if err != nil {
+		err = cli.NewExitError(err, 1)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "retimed %d typing streak(s)\n",
+		transformation.streaks)
+
+	return
+}
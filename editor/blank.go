@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/cirocosta/asciinema-edit/cast"
+)
+
+// BlankOptions configures how `Blank` hides the data of the events.
+type BlankOptions struct {
+	// Placeholder is printed in place of the first output event of
+	// each blanked section (e.g., `[redacted]`). When empty, nothing
+	// is printed.
+	Placeholder string
+
+	// KeepSequences keeps the control characters and escape sequences
+	// of output events, removing only the text. This way, line breaks,
+	// screen clears and cursor movements still take place, keeping the
+	// rest of the screen as it was.
+	//
+	// Note that escape sequences might carry text too (e.g., a window
+	// title).
+	KeepSequences bool
+}
+
+// Blank hides the data of the selected input and output events,
+// returning how many of them got blanked.
+//
+// Differently from `Cut`, events are not removed and their timestamps
+// are kept intact, thus the pacing of the cast doesn't change. Events
+// of other types (markers and resizes) are left untouched.
+func Blank(c *cast.Cast, selection Selection, options BlankOptions) (count int, err error) {
+	var ranges []IndexRange
+
+	ranges, err = selection.Ranges(c)
+	if err != nil {
+		return
+	}
+
+	carried := carriedSequences(c)
+
+	for _, r := range ranges {
+		placeholder := options.Placeholder
+
+		for idx := r.From; idx <= r.To; idx++ {
+			ev := c.EventStream[idx]
+
+			switch ev.Type {
+			case "i":
+				ev.Data = ""
+			case "o":
+				data := ""
+				if options.KeepSequences {
+					data = withoutText(carried[idx], ev.Data)
+				}
+
+				ev.Data = placeholder + data
+				placeholder = ""
+			default:
+				continue
+			}
+
+			count++
+		}
+	}
+
+	return
+}
+
+// withoutText removes the text from terminal output, keeping its
+// control characters and escape sequences.
+//
+// `carried` is the escape sequence that the preceding output left
+// unterminated (see `carriedSequences`), so that the beginning of
+// `data` is taken as its continuation rather than as text.
+func withoutText(carried, data string) string {
+	var builder strings.Builder
+
+	for _, token := range ansi.Tokenize(carried + data) {
+		if token.Kind == ansi.Text {
+			continue
+		}
+
+		builder.WriteString(token.Data)
+	}
+
+	return builder.String()[len(carried):]
+}
+
+// carriedSequences retrieves, for each event, the escape sequence that
+// the output events before it left unterminated (if any).
+func carriedSequences(c *cast.Cast) (carried []string) {
+	var pending string
+
+	carried = make([]string, len(c.EventStream))
+
+	for idx, ev := range c.EventStream {
+		carried[idx] = pending
+
+		if ev.Type != "o" {
+			continue
+		}
+
+		tokens := ansi.Tokenize(pending + ev.Data)
+		pending = ""
+
+		if len(tokens) != 0 && tokens[len(tokens)-1].Incomplete {
+			pending = tokens[len(tokens)-1].Data
+		}
+	}
+
+	return
+}
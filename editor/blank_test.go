@@ -0,0 +1,89 @@
+package editor_test
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Blank", func() {
+	var (
+		data      *cast.Cast
+		selection editor.Selection
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "Password: "},
+				{Time: 2, Type: "i", Data: "hunter2\r"},
+				{Time: 3, Type: "o", Data: "\x1b[1mhunter2\x1b[0m\r\n"},
+				{Time: 3.5, Type: "m", Data: "logged in"},
+				{Time: 4, Type: "o", Data: "$ "},
+			},
+		}
+		selection = editor.Selection{
+			Times: []editor.TimeRange{{From: 2, To: 3.5}},
+		}
+	})
+
+	It("fails with an empty event stream", func() {
+		_, err := editor.Blank(&cast.Cast{}, selection, editor.BlankOptions{})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("empties the selected events, keeping their timing", func() {
+		count, err := editor.Blank(data, selection, editor.BlankOptions{})
+		Expect(err).To(Succeed())
+		Expect(count).To(Equal(2))
+		Expect(data.EventStream).To(HaveLen(5))
+		Expect(data.EventStream[1].Time).To(Equal(2.0))
+		Expect(data.EventStream[1].Data).To(Equal(""))
+		Expect(data.EventStream[2].Time).To(Equal(3.0))
+		Expect(data.EventStream[2].Data).To(Equal(""))
+		Expect(data.EventStream[3].Data).To(Equal("logged in"))
+		Expect(data.EventStream[4].Data).To(Equal("$ "))
+	})
+
+	It("prints the placeholder once per section", func() {
+		selection = editor.Selection{
+			Match: regexp.MustCompile(`hunter2`),
+		}
+
+		_, err := editor.Blank(data, selection, editor.BlankOptions{
+			Placeholder: "[redacted]",
+		})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[1].Data).To(Equal(""))
+		Expect(data.EventStream[2].Data).To(Equal("[redacted]"))
+	})
+
+	It("keeps control characters and escape sequences", func() {
+		_, err := editor.Blank(data, selection, editor.BlankOptions{
+			KeepSequences: true,
+		})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[2].Data).To(Equal("\x1b[1m\x1b[0m\r\n"))
+	})
+	It("keeps escape sequences split across events", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 0.5, Type: "o", Data: "\x1b[31"},
+			{Time: 0.6, Type: "o", Data: "mred\x1b[0m"},
+			{Time: 1, Type: "o", Data: "$ "},
+		}
+		selection = editor.Selection{
+			Times: []editor.TimeRange{{From: 0.5, To: 0.6}},
+		}
+
+		_, err := editor.Blank(data, selection, editor.BlankOptions{
+			KeepSequences: true,
+		})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[0].Data).To(Equal("\x1b[31"))
+		Expect(data.EventStream[1].Data).To(Equal("m\x1b[0m"))
+	})
+})
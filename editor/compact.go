@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// CompactReport summarizes what `Compact` did.
+type CompactReport struct {
+	// Before is the number of events before compacting.
+	Before int
+	// After is the number of events after compacting.
+	After int
+}
+
+// Merged is the number of events that got merged into others.
+func (r *CompactReport) Merged() int {
+	return r.Before - r.After
+}
+
+// Compact merges consecutive output (`o`) and input (`i`) events of the
+// same type that happen less than `threshold` seconds after the first
+// event of the group they'd be merged into, such that no event gets
+// displayed more than `threshold` seconds earlier than it originally
+// was.
+func Compact(c *cast.Cast, threshold float64) (report CompactReport, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if threshold <= 0 {
+		err = errors.Errorf("threshold must be positive")
+		return
+	}
+
+	var (
+		res     = make([]*cast.Event, 0, len(c.EventStream))
+		group   *cast.Event
+		builder strings.Builder
+	)
+
+	flush := func() {
+		if group == nil {
+			return
+		}
+
+		group.Data = builder.String()
+		res = append(res, group)
+		group = nil
+		builder.Reset()
+	}
+
+	for _, ev := range c.EventStream {
+		if group != nil && group.Type == ev.Type &&
+			ev.Time-group.Time < threshold {
+			builder.WriteString(ev.Data)
+			continue
+		}
+
+		flush()
+
+		if ev.Type != "o" && ev.Type != "i" {
+			res = append(res, ev)
+			continue
+		}
+
+		group = ev
+		builder.WriteString(ev.Data)
+	}
+
+	flush()
+
+	report.Before = len(c.EventStream)
+	report.After = len(res)
+
+	c.EventStream = res
+	return
+}
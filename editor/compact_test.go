@@ -0,0 +1,79 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Compact", func() {
+	var (
+		data   *cast.Cast
+		report editor.CompactReport
+		err    error
+	)
+
+	events := func() (res []cast.Event) {
+		for _, ev := range data.EventStream {
+			res = append(res, *ev)
+		}
+		return
+	}
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "h"},
+				{Time: 1.001, Type: "o", Data: "e"},
+				{Time: 1.002, Type: "o", Data: "y"},
+				{Time: 1.003, Type: "i", Data: "l"},
+				{Time: 1.004, Type: "i", Data: "s"},
+				{Time: 1.005, Type: "m", Data: "marker"},
+				{Time: 1.006, Type: "o", Data: "a"},
+				{Time: 1.5, Type: "o", Data: "b"},
+			},
+		}
+	})
+
+	It("fails with nil cast", func() {
+		_, err = editor.Compact(nil, 1)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails with non-positive threshold", func() {
+		_, err = editor.Compact(data, 0)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("merges close events of the same type", func() {
+		report, err = editor.Compact(data, 0.01)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 1, Type: "o", Data: "hey"},
+			{Time: 1.003, Type: "i", Data: "ls"},
+			{Time: 1.005, Type: "m", Data: "marker"},
+			{Time: 1.006, Type: "o", Data: "a"},
+			{Time: 1.5, Type: "o", Data: "b"},
+		}))
+		Expect(report.Before).To(Equal(8))
+		Expect(report.After).To(Equal(5))
+		Expect(report.Merged()).To(Equal(3))
+	})
+
+	It("limits groups to the threshold since their first event", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 0, Type: "o", Data: "a"},
+			{Time: 0.6, Type: "o", Data: "b"},
+			{Time: 1.2, Type: "o", Data: "c"},
+		}
+
+		_, err = editor.Compact(data, 1)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 0, Type: "o", Data: "ab"},
+			{Time: 1.2, Type: "o", Data: "c"},
+		}))
+	})
+})
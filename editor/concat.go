@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// ConcatOptions configures how `Concat` joins casts.
+type ConcatOptions struct {
+	// Gap is the delay (in seconds) between the last event of a cast
+	// and the first event of the next one.
+	Gap float64
+
+	// Policy determines how differing terminal sizes get reconciled
+	// (see `SizePolicy`).
+	Policy SizePolicy
+
+	// Markers makes a marker event (`m`) be added at the beginning of
+	// each cast that gets joined.
+	Markers bool
+
+	// Labels contains the labels of the markers (one per cast).
+	//
+	// If not specified, markers are labeled after the position of
+	// the cast (e.g., `part 2`).
+	Labels []string
+}
+
+// Concat joins a list of casts into a single one, in order.
+//
+// The events of each cast get their timestamps offset so that they
+// happen after the last event of the previous cast (plus a gap, if
+// specified).
+//
+// The resulting cast has the header of the first cast, with its
+// size possibly updated according to the size policy.
+//
+// It assumes that all casts are entirely valid (see
+// `github.com/cirocosta/asciinema-edit/cast#Validate`).
+func Concat(casts []*cast.Cast, options ConcatOptions) (res *cast.Cast, err error) {
+	if len(casts) == 0 {
+		err = errors.Errorf("at least one cast must be specified")
+		return
+	}
+
+	if options.Gap < 0 {
+		err = errors.Errorf("gap must not be negative")
+		return
+	}
+
+	if options.Labels != nil && len(options.Labels) != len(casts) {
+		err = errors.Errorf("one label per cast must be specified")
+		return
+	}
+
+	for idx, c := range casts {
+		if c == nil {
+			err = errors.Errorf("cast %d must not be nil", idx+1)
+			return
+		}
+	}
+
+	res = &cast.Cast{
+		Header:      casts[0].Header,
+		EventStream: make([]*cast.Event, 0),
+	}
+
+	var (
+		offset float64
+		width  = res.Header.Width
+		height = res.Header.Height
+	)
+
+	for idx, c := range casts {
+		if idx > 0 {
+			offset = options.Gap
+			if len(res.EventStream) > 0 {
+				offset += res.EventStream[len(res.EventStream)-1].Time
+			}
+		}
+
+		if width != c.Header.Width || height != c.Header.Height {
+			switch options.Policy {
+			case SizeFail:
+				err = errors.Errorf(
+					"size mismatch: cast %d is %dx%d, previous is %dx%d",
+					idx+1, c.Header.Width, c.Header.Height,
+					width, height)
+				res = nil
+				return
+			case SizeMax:
+				res.Header.Width = maxUint(res.Header.Width, c.Header.Width)
+				res.Header.Height = maxUint(res.Header.Height, c.Header.Height)
+			case SizeResize:
+				res.EventStream = append(res.EventStream,
+					resizeEvent(offset, c.Header.Width, c.Header.Height))
+			default:
+				err = errors.Errorf("unknown size policy '%s'", options.Policy)
+				res = nil
+				return
+			}
+		}
+
+		if options.Markers && idx > 0 {
+			label := fmt.Sprintf("part %d", idx+1)
+			if options.Labels != nil {
+				label = options.Labels[idx]
+			}
+
+			res.EventStream = append(res.EventStream, &cast.Event{
+				Time: offset,
+				Type: "m",
+				Data: label,
+			})
+		}
+
+		for _, ev := range c.EventStream {
+			res.EventStream = append(res.EventStream, &cast.Event{
+				Time: ev.Time + offset,
+				Type: ev.Type,
+				Data: ev.Data,
+			})
+		}
+
+		width, height = sizeAt(c, len(c.EventStream))
+	}
+
+	roundTimes(res.EventStream)
+	return
+}
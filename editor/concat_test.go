@@ -0,0 +1,142 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Concat", func() {
+	var (
+		first, second *cast.Cast
+		options       editor.ConcatOptions
+	)
+
+	BeforeEach(func() {
+		first = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 80, Height: 24, Title: "first"},
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+			},
+		}
+
+		second = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 80, Height: 24, Title: "second"},
+			EventStream: []*cast.Event{
+				{Time: 0.5, Type: "o", Data: "c"},
+				{Time: 1, Type: "o", Data: "d"},
+			},
+		}
+
+		options = editor.ConcatOptions{
+			Policy: editor.SizeFail,
+		}
+	})
+
+	Describe("parameter validation", func() {
+		It("fails without casts", func() {
+			_, err := editor.Concat(nil, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with nil casts", func() {
+			_, err := editor.Concat([]*cast.Cast{first, nil}, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with negative gap", func() {
+			options.Gap = -1
+			_, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with a label count that doesn't match", func() {
+			options.Labels = []string{"a"}
+			_, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	Context("with casts of the same size", func() {
+		It("joins the events in order", func() {
+			res, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).To(Succeed())
+			Expect(res.Header.Title).To(Equal("first"))
+			Expect(res.EventStream).To(Equal([]*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+				{Time: 2.5, Type: "o", Data: "c"},
+				{Time: 3, Type: "o", Data: "d"},
+			}))
+		})
+
+		It("leaves the original casts untouched", func() {
+			_, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).To(Succeed())
+			Expect(second.EventStream[0].Time).To(Equal(0.5))
+		})
+
+		It("adds the gap between casts", func() {
+			options.Gap = 1
+			res, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).To(Succeed())
+			Expect(res.EventStream[2].Time).To(Equal(3.5))
+		})
+
+		It("adds markers at each join", func() {
+			options.Markers = true
+			res, err := editor.Concat([]*cast.Cast{first, second, second}, options)
+			Expect(err).To(Succeed())
+			Expect(res.EventStream).To(HaveLen(8))
+			Expect(res.EventStream[2]).To(Equal(&cast.Event{
+				Time: 2, Type: "m", Data: "part 2",
+			}))
+			Expect(res.EventStream[5]).To(Equal(&cast.Event{
+				Time: 3, Type: "m", Data: "part 3",
+			}))
+		})
+
+		It("labels markers", func() {
+			options.Markers = true
+			options.Labels = []string{"intro", "demo"}
+			res, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).To(Succeed())
+			Expect(res.EventStream[2].Data).To(Equal("demo"))
+		})
+	})
+
+	Context("with casts of different sizes", func() {
+		BeforeEach(func() {
+			second.Header.Width = 100
+		})
+
+		It("fails with the fail policy", func() {
+			_, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("takes the biggest dimensions with the max policy", func() {
+			options.Policy = editor.SizeMax
+			res, err := editor.Concat([]*cast.Cast{first, second}, options)
+			Expect(err).To(Succeed())
+			Expect(res.Header.Width).To(Equal(uint(100)))
+			Expect(res.EventStream).To(HaveLen(4))
+		})
+
+		It("emits resize events with the resize policy", func() {
+			options.Policy = editor.SizeResize
+			res, err := editor.Concat([]*cast.Cast{first, second, first}, options)
+			Expect(err).To(Succeed())
+			Expect(res.Header.Width).To(Equal(uint(80)))
+			Expect(res.EventStream[2]).To(Equal(&cast.Event{
+				Time: 2, Type: "r", Data: "100x24",
+			}))
+			Expect(res.EventStream[5]).To(Equal(&cast.Event{
+				Time: 3, Type: "r", Data: "80x24",
+			}))
+		})
+	})
+})
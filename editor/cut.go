@@ -1,8 +1,6 @@
 package editor
 
 import (
-	"math"
-
 	"github.com/cirocosta/asciinema-edit/cast"
 	"github.com/pkg/errors"
 )
@@ -66,17 +64,62 @@ func Cut(c *cast.Cast, from, to float64) (err error) {
 		return
 	}
 
-	if toIdx+1 < len(c.EventStream) {
-		delta := c.EventStream[toIdx+1].Time - c.EventStream[fromIdx].Time
-		for _, remainingElem := range c.EventStream[toIdx+1:] {
-			remainingElem.Time -= delta
-			remainingElem.Time = math.Round(remainingElem.Time*1000) / 1000
+	err = CutIndex(c, fromIdx, toIdx)
+	return
+}
+
+// CutIndex removes the events from position `from` to position `to`
+// (both included) from the event stream, bringing the remaining events
+// closer (just like `Cut` does).
+func CutIndex(c *cast.Cast, from, to int) (err error) {
+	if c == nil {
+		err = errors.Errorf("a cast must be specified")
+		return
+	}
+
+	err = IndexRange{From: from, To: to}.validate(c)
+	if err != nil {
+		return
+	}
+
+	if to+1 < len(c.EventStream) {
+		delta := c.EventStream[to+1].Time - c.EventStream[from].Time
+		for _, remainingElem := range c.EventStream[to+1:] {
+			remainingElem.Time = RoundTime(remainingElem.Time - delta)
 		}
 	}
 
 	c.EventStream = append(
-		c.EventStream[:fromIdx],
-		c.EventStream[toIdx+1:]...)
+		c.EventStream[:from],
+		c.EventStream[to+1:]...)
+
+	return
+}
+
+// CutSelection removes the selected events from the event stream,
+// bringing the remaining events closer (just like `Cut` does).
+//
+// Fails if all of the events are selected.
+func CutSelection(c *cast.Cast, selection Selection) (err error) {
+	var ranges []IndexRange
+
+	ranges, err = selection.Ranges(c)
+	if err != nil {
+		return
+	}
+
+	if len(ranges) == 1 &&
+		ranges[0].From == 0 && ranges[0].To == len(c.EventStream)-1 {
+		err = errors.Errorf("selection must not cover the whole event stream")
+		return
+	}
+
+	for i := len(ranges) - 1; i >= 0; i-- {
+		err = CutIndex(c, ranges[i].From, ranges[i].To)
+		if err != nil {
+			return
+		}
+	}
 
 	return
 }
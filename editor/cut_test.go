@@ -1,6 +1,8 @@
 package editor_test
 
 import (
+	"regexp"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -148,3 +150,77 @@ var _ = Describe("Cut", func() {
 		})
 	})
 })
+
+var _ = Describe("CutIndex", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+				{Time: 2, Type: "o", Data: "c"},
+				{Time: 3, Type: "o", Data: "d"},
+			},
+		}
+	})
+
+	It("fails with out of bounds indexes", func() {
+		Expect(editor.CutIndex(data, -1, 1)).ToNot(Succeed())
+		Expect(editor.CutIndex(data, 1, 4)).ToNot(Succeed())
+	})
+
+	It("fails with from > to", func() {
+		Expect(editor.CutIndex(data, 2, 1)).ToNot(Succeed())
+	})
+
+	It("removes a single event among events sharing its timestamp", func() {
+		Expect(editor.CutIndex(data, 1, 1)).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(3))
+		Expect(data.EventStream[1].Data).To(Equal("c"))
+		Expect(data.EventStream[1].Time).To(Equal(2.0))
+		Expect(data.EventStream[2].Time).To(Equal(3.0))
+	})
+
+	It("removes a range of events bringing the remaining closer", func() {
+		Expect(editor.CutIndex(data, 1, 2)).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(2))
+		Expect(data.EventStream[1].Data).To(Equal("d"))
+		Expect(data.EventStream[1].Time).To(Equal(2.0))
+	})
+})
+
+var _ = Describe("CutSelection", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "secret"},
+				{Time: 3, Type: "o", Data: "b"},
+				{Time: 4, Type: "o", Data: "secret"},
+				{Time: 5, Type: "o", Data: "c"},
+			},
+		}
+	})
+
+	It("removes every selected range", func() {
+		err := editor.CutSelection(data, editor.Selection{
+			Match: regexp.MustCompile(`secret`),
+		})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(3))
+		Expect(data.EventStream[0].Time).To(Equal(1.0))
+		Expect(data.EventStream[1].Time).To(Equal(2.0))
+		Expect(data.EventStream[1].Data).To(Equal("b"))
+		Expect(data.EventStream[2].Time).To(Equal(3.0))
+		Expect(data.EventStream[2].Data).To(Equal("c"))
+	})
+
+	It("fails when selecting the whole event stream", func() {
+		err := editor.CutSelection(data, editor.Selection{})
+		Expect(err).ToNot(Succeed())
+		Expect(data.EventStream).To(HaveLen(5))
+	})
+})
@@ -0,0 +1,173 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/terminal"
+	"github.com/pkg/errors"
+)
+
+// DedupeOptions configures what `Dedupe` removes.
+type DedupeOptions struct {
+	// Spinners collapses the frames of spinners into a single held
+	// frame (see `Dedupe`).
+	Spinners bool
+}
+
+// DedupeReport summarizes the events removed by `Dedupe`.
+type DedupeReport struct {
+	// NoOps is the number of output events that didn't change the
+	// screen.
+	NoOps int
+	// SpinnerFrames is the number of spinner frames removed.
+	SpinnerFrames int
+}
+
+// Removed is the total number of events removed.
+func (r DedupeReport) Removed() int {
+	return r.NoOps + r.SpinnerFrames
+}
+
+// Dedupe removes the output events (`o`) that don't change what the
+// terminal displays, such as progress bars redrawing the same content.
+//
+// The event stream is rendered through a terminal emulator: an event
+// is a no-op when the terminal is left in the very same state (screen,
+// cursor, text attributes, modes, ...) as before it, thus removing it
+// never changes what comes after. The timestamps of the remaining
+// events are kept intact.
+//
+// Events carrying sequences that the emulator doesn't interpret are
+// always kept, given that their effect can't be known. Sequences that
+// are known not to affect what gets displayed (e.g., window titles or
+// keyboard modes) are not taken into account, thus events made only of
+// them are removed.
+//
+// With `options.Spinners`, consecutive output events that only change
+// the same cell of the screen (leaving the cursor where it was) and
+// repeat themselves are taken as the frames of a spinner: the first
+// frame is held until the spinner ends, while the frames in between
+// are removed. The last frame is kept if it draws something different
+// from the first one, so that the spinner ends just like it did.
+func Dedupe(c *cast.Cast, options DedupeOptions) (report DedupeReport, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if c.Header.Width == 0 || c.Header.Height == 0 {
+		err = errors.Errorf("cast header must specify the terminal size")
+		return
+	}
+
+	var (
+		term = terminal.New(int(c.Header.Width), int(c.Header.Height))
+		kept = make([]*cast.Event, 0, len(c.EventStream))
+		run  = &spinnerRun{}
+	)
+
+	flush := func() {
+		frames := run.collapse()
+		report.SpinnerFrames += len(run.events) - len(frames)
+		kept = append(kept, frames...)
+		run = &spinnerRun{}
+	}
+
+	for _, ev := range c.EventStream {
+		if ev.Type != "o" {
+			if ev.Type == "r" {
+				width, height, sizeErr := cast.ParseSize(ev.Data)
+				if sizeErr == nil {
+					term.Resize(int(width), int(height))
+				}
+			}
+
+			flush()
+			kept = append(kept, ev)
+			continue
+		}
+
+		before := term.Clone()
+		term.Write(ev.Data)
+
+		if term.Unhandled() != before.Unhandled() {
+			flush()
+			kept = append(kept, ev)
+			continue
+		}
+
+		changes, ok := term.Changes(before)
+		if ok && len(changes) == 0 {
+			report.NoOps++
+			continue
+		}
+
+		if options.Spinners && ok && len(changes) == 1 {
+			if !run.add(ev, changes[0]) {
+				flush()
+				run.add(ev, changes[0])
+			}
+			continue
+		}
+
+		flush()
+		kept = append(kept, ev)
+	}
+
+	flush()
+
+	c.EventStream = kept
+	return
+}
+
+// spinnerRun gathers consecutive output events that only change the
+// same cell of the screen.
+type spinnerRun struct {
+	events   []*cast.Event
+	position terminal.Position
+}
+
+// add appends an event that changes the cell at `position` to the run,
+// failing if the run is about another cell.
+func (r *spinnerRun) add(ev *cast.Event, position terminal.Position) bool {
+	if len(r.events) != 0 && r.position != position {
+		return false
+	}
+
+	r.events = append(r.events, ev)
+	r.position = position
+	return true
+}
+
+// collapse retrieves the events of the run that must be kept: all of
+// them if no frame repeats (thus it's not a spinner), or the first and
+// the last frames otherwise.
+func (r *spinnerRun) collapse() []*cast.Event {
+	var (
+		seen     = make(map[string]bool, len(r.events))
+		repeated bool
+	)
+
+	for _, ev := range r.events {
+		if seen[ev.Data] {
+			repeated = true
+			break
+		}
+
+		seen[ev.Data] = true
+	}
+
+	if !repeated {
+		return r.events
+	}
+
+	var (
+		first = r.events[0]
+		last  = r.events[len(r.events)-1]
+	)
+
+	if first.Data == last.Data {
+		return []*cast.Event{first}
+	}
+
+	return []*cast.Event{first, last}
+}
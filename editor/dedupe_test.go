@@ -0,0 +1,171 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Dedupe", func() {
+	var data *cast.Cast
+
+	datas := func() (res []string) {
+		for _, ev := range data.EventStream {
+			res = append(res, ev.Data)
+		}
+		return
+	}
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 20, Height: 5},
+		}
+	})
+
+	It("fails without the terminal size", func() {
+		data.Header.Width = 0
+
+		_, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("removes output that doesn't change the screen", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 1, Type: "o", Data: "\r[==  ] 50%"},
+			{Time: 2, Type: "o", Data: "\r[==  ] 50%"},
+			{Time: 3, Type: "o", Data: "\r[==  ] 50%"},
+			{Time: 4, Type: "o", Data: "\r[=== ] 75%"},
+		}
+
+		report, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).To(Succeed())
+		Expect(report.NoOps).To(Equal(2))
+		Expect(data.EventStream).To(HaveLen(2))
+		Expect(data.EventStream[0].Time).To(Equal(1.0))
+		Expect(data.EventStream[1].Time).To(Equal(4.0))
+	})
+
+	It("keeps output that changes the terminal state", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 1, Type: "o", Data: "a"},
+			{Time: 2, Type: "o", Data: "\x1b[1m"},
+			{Time: 3, Type: "o", Data: "\x1b["},
+			{Time: 4, Type: "o", Data: "0m"},
+		}
+
+		report, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).To(Succeed())
+		Expect(report.Removed()).To(Equal(0))
+		Expect(data.EventStream).To(HaveLen(4))
+	})
+
+	It("keeps changes of character set and screen mode", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 1, Type: "o", Data: "\x1b(0"},
+			{Time: 2, Type: "o", Data: "lqqk"},
+			{Time: 3, Type: "o", Data: "\x1b(B"},
+			{Time: 4, Type: "o", Data: "\x1b[?5h"},
+		}
+
+		report, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).To(Succeed())
+		Expect(report.Removed()).To(Equal(0))
+		Expect(data.EventStream).To(HaveLen(4))
+	})
+
+	It("keeps events with sequences it can't interpret", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 1, Type: "o", Data: "a"},
+			{Time: 2, Type: "o", Data: "\x1b]4;1;rgb:ff/00/00\x07"},
+			{Time: 3, Type: "o", Data: "\x1b]2;title\x07"},
+		}
+
+		report, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).To(Succeed())
+		Expect(report.NoOps).To(Equal(1))
+		Expect(datas()).To(Equal([]string{
+			"a",
+			"\x1b]4;1;rgb:ff/00/00\x07",
+		}))
+	})
+
+	It("keeps events of other types", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 1, Type: "o", Data: "a"},
+			{Time: 2, Type: "i", Data: ""},
+			{Time: 3, Type: "m", Data: ""},
+		}
+
+		_, err := editor.Dedupe(data, editor.DedupeOptions{})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(3))
+	})
+
+	Describe("with spinners", func() {
+		BeforeEach(func() {
+			data.EventStream = []*cast.Event{
+				{Time: 1, Type: "o", Data: "| installing"},
+				{Time: 2, Type: "o", Data: "\r/\x1b[11C"},
+				{Time: 3, Type: "o", Data: "\r-\x1b[11C"},
+				{Time: 4, Type: "o", Data: "\r\\\x1b[11C"},
+				{Time: 5, Type: "o", Data: "\r|\x1b[11C"},
+				{Time: 6, Type: "o", Data: "\r/\x1b[11C"},
+				{Time: 7, Type: "o", Data: "\r\n$ "},
+			}
+		})
+
+		It("keeps the frames without the option", func() {
+			_, err := editor.Dedupe(data, editor.DedupeOptions{})
+			Expect(err).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(7))
+		})
+
+		It("holds the first frame", func() {
+			report, err := editor.Dedupe(data, editor.DedupeOptions{
+				Spinners: true,
+			})
+			Expect(err).To(Succeed())
+			Expect(report.SpinnerFrames).To(Equal(4))
+			Expect(datas()).To(Equal([]string{
+				"| installing",
+				"\r/\x1b[11C",
+				"\r\n$ ",
+			}))
+		})
+
+		It("keeps the last frame if it differs", func() {
+			data.EventStream[4].Data = "\r/\x1b[11C"
+			data.EventStream[5].Data = "\r*\x1b[11C"
+
+			report, err := editor.Dedupe(data, editor.DedupeOptions{
+				Spinners: true,
+			})
+			Expect(err).To(Succeed())
+			Expect(report.SpinnerFrames).To(Equal(3))
+			Expect(datas()).To(Equal([]string{
+				"| installing",
+				"\r/\x1b[11C",
+				"\r*\x1b[11C",
+				"\r\n$ ",
+			}))
+		})
+
+		It("keeps counters that don't repeat", func() {
+			data.EventStream = []*cast.Event{
+				{Time: 1, Type: "o", Data: "1"},
+				{Time: 2, Type: "o", Data: "\b2"},
+				{Time: 3, Type: "o", Data: "\b3"},
+				{Time: 4, Type: "o", Data: "\b4"},
+			}
+
+			report, err := editor.Dedupe(data, editor.DedupeOptions{
+				Spinners: true,
+			})
+			Expect(err).To(Succeed())
+			Expect(report.Removed()).To(Equal(0))
+			Expect(data.EventStream).To(HaveLen(4))
+		})
+	})
+})
@@ -5,9 +5,19 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
 )
 
 func TestEditor(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Editor Suite")
 }
+
+// times retrieves the timestamps of the events of a cast.
+func times(c *cast.Cast) (res []float64) {
+	for _, ev := range c.EventStream {
+		res = append(res, ev.Time)
+	}
+	return
+}
@@ -0,0 +1,247 @@
+package editor
+
+import (
+	"sort"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// FitStrategy determines how delays get scaled by `Fit`.
+type FitStrategy string
+
+const (
+	// FitUniform multiplies all delays by the same factor.
+	FitUniform FitStrategy = "uniform"
+	// FitPauses shortens the longest delays first, capping them all
+	// at the same value.
+	FitPauses FitStrategy = "pauses"
+)
+
+// ParseFitStrategy converts a string into a FitStrategy.
+func ParseFitStrategy(input string) (strategy FitStrategy, err error) {
+	strategy = FitStrategy(input)
+
+	switch strategy {
+	case FitUniform, FitPauses:
+	default:
+		err = errors.Errorf(
+			"unknown fit strategy '%s' (must be uniform or pauses)", input)
+	}
+
+	return
+}
+
+// FitOptions configures how `Fit` scales a cast.
+type FitOptions struct {
+	// Duration is the total duration (in seconds) that the cast must
+	// have.
+	Duration float64
+
+	// Strategy determines how delays get scaled.
+	Strategy FitStrategy
+
+	// Compressible restricts the delays that can be scaled to those
+	// within the ranges (in the original timestamps). When empty, all
+	// delays can be scaled.
+	Compressible []TimeRange
+}
+
+// FitSegment describes a portion of the original cast (from `From` to
+// `To`) whose delays have been multiplied by `Factor`.
+type FitSegment struct {
+	From   float64
+	To     float64
+	Factor float64
+}
+
+// Fit scales the delays of a cast so that it lasts exactly
+// `options.Duration` (i.e., the last event happens at such time),
+// returning the segments that got changed.
+//
+// With the uniform strategy, all scalable delays get multiplied by the
+// same factor. With the pauses strategy, the longest delays get
+// shortened first: a cap is computed such that limiting the scalable
+// delays to it makes the cast fit the target. As there's no pause to
+// shorten when stretching, the pauses strategy scales uniformly in that
+// case.
+//
+// The delay before the first event is part of the duration, thus it can
+// be scaled as well.
+func Fit(c *cast.Cast, options FitOptions) (segments []FitSegment, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if options.Duration <= 0 {
+		err = errors.Errorf("duration must be positive")
+		return
+	}
+
+	var (
+		count    = len(c.EventStream)
+		times    = make([]float64, count+1)
+		delays   = make([]float64, count)
+		factors  = make([]float64, count)
+		scalable = make([]int, 0, count)
+		total    = c.EventStream[count-1].Time
+		fixed    = total
+		target   float64
+		sum      float64
+		i        int
+	)
+
+	for i = 0; i < count; i++ {
+		times[i+1] = c.EventStream[i].Time
+		delays[i] = times[i+1] - times[i]
+		factors[i] = 1
+
+		if !isCompressible(options.Compressible, times[i], times[i+1]) {
+			continue
+		}
+
+		scalable = append(scalable, i)
+		sum += delays[i]
+	}
+
+	if sum <= 0 {
+		err = errors.Errorf("cast has no delays that can be scaled")
+		return
+	}
+
+	fixed -= sum
+	target = options.Duration - fixed
+
+	if target < 0 {
+		err = errors.Errorf(
+			"can't fit the cast in %gs: the delays that can't be scaled "+
+				"already take %gs", options.Duration, fixed)
+		return
+	}
+
+	switch options.Strategy {
+	case FitUniform:
+		fitUniform(scalable, factors, target/sum)
+	case FitPauses:
+		if target >= sum {
+			fitUniform(scalable, factors, target/sum)
+			break
+		}
+
+		fitPauses(scalable, delays, factors, target)
+	default:
+		err = errors.Errorf("unknown fit strategy '%s'", options.Strategy)
+		return
+	}
+
+	var shift float64
+
+	for i = 0; i < count; i++ {
+		shift += delays[i] * (factors[i] - 1)
+		c.EventStream[i].Time = times[i+1] + shift
+	}
+
+	roundTimes(c.EventStream)
+	c.EventStream[count-1].Time = options.Duration
+
+	segments = fitSegments(times, delays, factors)
+	return
+}
+
+// isCompressible verifies whether the delay between `from` and `to` can
+// be scaled.
+func isCompressible(ranges []TimeRange, from, to float64) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+
+	for _, r := range ranges {
+		if r.Contains(from, to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fitUniform sets the same factor to all of the scalable delays.
+func fitUniform(scalable []int, factors []float64, factor float64) {
+	for _, idx := range scalable {
+		factors[idx] = factor
+	}
+}
+
+// fitPauses finds the cap that makes the scalable delays sum up to
+// `target`, setting the factors of the delays above it accordingly.
+//
+// With the delays sorted in descending order, capping the `k` longest
+// ones at `limit` leads to `k*limit + rest`, where `rest` is the sum of
+// the remaining delays. The cap is the first `limit` that is not
+// smaller than the longest of the remaining delays.
+func fitPauses(scalable []int, delays, factors []float64, target float64) {
+	var (
+		sorted = make([]int, len(scalable))
+		rest   float64
+		limit  float64
+	)
+
+	copy(sorted, scalable)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return delays[sorted[i]] > delays[sorted[j]]
+	})
+
+	for _, idx := range sorted {
+		rest += delays[idx]
+	}
+
+	for k := 1; k <= len(sorted); k++ {
+		rest -= delays[sorted[k-1]]
+		limit = (target - rest) / float64(k)
+
+		if k == len(sorted) || limit >= delays[sorted[k]] {
+			break
+		}
+	}
+
+	for _, idx := range sorted {
+		if delays[idx] <= limit {
+			break
+		}
+
+		factors[idx] = limit / delays[idx]
+	}
+}
+
+// fitSegments groups consecutive delays that got scaled by the same
+// factor.
+func fitSegments(times, delays, factors []float64) (segments []FitSegment) {
+	segments = make([]FitSegment, 0)
+
+	for i := range delays {
+		if factors[i] == 1 || delays[i] == 0 {
+			continue
+		}
+
+		last := len(segments) - 1
+		if last >= 0 &&
+			segments[last].To == times[i] &&
+			segments[last].Factor == factors[i] {
+			segments[last].To = times[i+1]
+			continue
+		}
+
+		segments = append(segments, FitSegment{
+			From:   times[i],
+			To:     times[i+1],
+			Factor: factors[i],
+		})
+	}
+
+	return
+}
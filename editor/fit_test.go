@@ -0,0 +1,156 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Fit", func() {
+	var (
+		data     *cast.Cast
+		err      error
+		segments []editor.FitSegment
+		options  editor.FitOptions
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+				{Time: 5, Type: "o", Data: "c"},
+				{Time: 9, Type: "o", Data: "d"},
+				{Time: 10, Type: "o", Data: "e"},
+			},
+		}
+
+		options = editor.FitOptions{
+			Strategy: editor.FitUniform,
+		}
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			_, err = editor.Fit(nil, editor.FitOptions{Duration: 1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with empty event stream", func() {
+			_, err = editor.Fit(&cast.Cast{}, editor.FitOptions{Duration: 1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with non-positive duration", func() {
+			_, err = editor.Fit(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with unknown strategy", func() {
+			options.Duration = 5
+			options.Strategy = "fastest"
+
+			_, err = editor.Fit(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	Context("with the uniform strategy", func() {
+		It("compresses all delays", func() {
+			options.Duration = 5
+
+			segments, err = editor.Fit(data, options)
+			Expect(err).To(Succeed())
+			Expect(times(data)).To(Equal([]float64{0.5, 1, 2.5, 4.5, 5}))
+			Expect(segments).To(Equal([]editor.FitSegment{
+				{From: 0, To: 10, Factor: 0.5},
+			}))
+		})
+
+		It("stretches all delays", func() {
+			options.Duration = 20
+
+			segments, err = editor.Fit(data, options)
+			Expect(err).To(Succeed())
+			Expect(times(data)).To(Equal([]float64{2, 4, 10, 18, 20}))
+			Expect(segments).To(Equal([]editor.FitSegment{
+				{From: 0, To: 10, Factor: 2},
+			}))
+		})
+	})
+
+	Context("with the pauses strategy", func() {
+		BeforeEach(func() {
+			options.Strategy = editor.FitPauses
+		})
+
+		It("caps the longest pauses", func() {
+			options.Duration = 8
+
+			segments, err = editor.Fit(data, options)
+			Expect(err).To(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 4.5, 7, 8}))
+			Expect(segments).To(HaveLen(2))
+			Expect(segments[0].From).To(Equal(2.0))
+			Expect(segments[0].To).To(Equal(5.0))
+			Expect(segments[0].Factor).To(BeNumerically("~", 2.5/3))
+			Expect(segments[1]).To(Equal(editor.FitSegment{
+				From: 5, To: 9, Factor: 0.625,
+			}))
+		})
+
+		It("stretches uniformly", func() {
+			options.Duration = 20
+
+			_, err = editor.Fit(data, options)
+			Expect(err).To(Succeed())
+			Expect(times(data)).To(Equal([]float64{2, 4, 10, 18, 20}))
+		})
+	})
+
+	Context("with compressible ranges", func() {
+		It("only scales the delays within the ranges", func() {
+			options.Duration = 8
+			options.Compressible = []editor.TimeRange{{From: 5, To: 9}}
+
+			segments, err = editor.Fit(data, options)
+			Expect(err).To(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 5, 7, 8}))
+			Expect(segments).To(Equal([]editor.FitSegment{
+				{From: 5, To: 9, Factor: 0.5},
+			}))
+		})
+
+		It("fails if the target can't be reached", func() {
+			options.Duration = 5
+			options.Compressible = []editor.TimeRange{{From: 5, To: 9}}
+
+			_, err = editor.Fit(data, options)
+			Expect(err).ToNot(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 5, 9, 10}))
+		})
+
+		It("fails if no delay lies within the ranges", func() {
+			options.Duration = 5
+			options.Compressible = []editor.TimeRange{{From: 1.5, To: 1.8}}
+
+			_, err = editor.Fit(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+})
+
+var _ = Describe("ParseFitStrategy", func() {
+	It("accepts known strategies", func() {
+		strategy, err := editor.ParseFitStrategy("pauses")
+		Expect(err).To(Succeed())
+		Expect(strategy).To(Equal(editor.FitPauses))
+	})
+
+	It("fails with unknown strategies", func() {
+		_, err := editor.ParseFitStrategy("fastest")
+		Expect(err).ToNot(Succeed())
+	})
+})
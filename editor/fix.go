@@ -0,0 +1,102 @@
+package editor
+
+import (
+	"sort"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// FixOptions configures how `Fix` repairs an event stream.
+type FixOptions struct {
+	// Sort makes out-of-order events get moved to the position that
+	// their timestamps indicate.
+	//
+	// By default, out-of-order events keep their position and have
+	// their timestamps raised to the one of the previous event
+	// (monotonization).
+	Sort bool
+
+	// DropDuplicates removes events that are exactly equal (same time,
+	// type and data) to the event that precedes them.
+	DropDuplicates bool
+}
+
+// FixReport summarizes the changes performed by `Fix`.
+type FixReport struct {
+	// NegativeTimes is the number of events that had a negative
+	// timestamp set to zero.
+	NegativeTimes int
+
+	// OutOfOrder is the number of events that were either moved
+	// or had their timestamp raised.
+	OutOfOrder int
+
+	// Duplicates is the number of duplicated events removed.
+	Duplicates int
+}
+
+// Changed indicates whether any modification has been performed.
+func (r FixReport) Changed() bool {
+	return r.NegativeTimes+r.OutOfOrder+r.Duplicates > 0
+}
+
+// Fix repairs the event stream of a cast so that it becomes ordered by
+// time (see `github.com/cirocosta/asciinema-edit/cast#ValidateEventStream`).
+//
+// The heuristic is:
+//
+// 1. set negative timestamps to zero; then
+// 2. either sort or monotonize out-of-order events; then
+// 3. (optionally) remove duplicated events.
+func Fix(c *cast.Cast, options FixOptions) (report FixReport, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	var latest float64
+
+	for _, ev := range c.EventStream {
+		if ev.Time < 0 {
+			ev.Time = 0
+			report.NegativeTimes++
+		}
+
+		if ev.Time < latest {
+			report.OutOfOrder++
+
+			if !options.Sort {
+				ev.Time = latest
+			}
+		}
+
+		if ev.Time > latest {
+			latest = ev.Time
+		}
+	}
+
+	if options.Sort {
+		sort.SliceStable(c.EventStream, func(i, j int) bool {
+			return c.EventStream[i].Time < c.EventStream[j].Time
+		})
+	}
+
+	if !options.DropDuplicates || len(c.EventStream) == 0 {
+		return
+	}
+
+	var deduped = c.EventStream[:1]
+
+	for _, ev := range c.EventStream[1:] {
+		if *ev == *deduped[len(deduped)-1] {
+			report.Duplicates++
+			continue
+		}
+
+		deduped = append(deduped, ev)
+	}
+
+	c.EventStream = deduped
+	return
+}
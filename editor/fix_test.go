@@ -0,0 +1,120 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Fix", func() {
+	Describe("parameter validation", func() {
+		Context("with nil cast", func() {
+			It("fails", func() {
+				_, err := editor.Fix(nil, editor.FixOptions{})
+				Expect(err).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("with a broken event stream", func() {
+		var (
+			data                           *cast.Cast
+			event1, event2, event3, event4 *cast.Event
+			duplicate                      *cast.Event
+			report                         editor.FixReport
+			options                        editor.FixOptions
+			err                            error
+		)
+
+		BeforeEach(func() {
+			options = editor.FixOptions{}
+
+			event1 = &cast.Event{Time: -1, Type: "o", Data: "event1"}
+			event2 = &cast.Event{Time: 2, Type: "o", Data: "event2"}
+			event3 = &cast.Event{Time: 1, Type: "o", Data: "event3"}
+			event4 = &cast.Event{Time: 3, Type: "o", Data: "event4"}
+			duplicate = &cast.Event{Time: 3, Type: "o", Data: "event4"}
+
+			data = &cast.Cast{
+				EventStream: []*cast.Event{
+					event1,
+					event2,
+					event3,
+					event4,
+					duplicate,
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			report, err = editor.Fix(data, options)
+			Expect(err).To(Succeed())
+		})
+
+		It("results in a valid event stream", func() {
+			_, err = cast.ValidateEventStream(data.EventStream)
+			Expect(err).To(Succeed())
+		})
+
+		It("sets negative times to zero", func() {
+			Expect(event1.Time).To(Equal(float64(0)))
+			Expect(report.NegativeTimes).To(Equal(1))
+		})
+
+		It("monotonizes out-of-order events by default", func() {
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				event1, event2, event3, event4, duplicate,
+			}))
+			Expect(event3.Time).To(Equal(float64(2)))
+			Expect(report.OutOfOrder).To(Equal(1))
+		})
+
+		It("keeps duplicates by default", func() {
+			Expect(report.Duplicates).To(Equal(0))
+			Expect(report.Changed()).To(BeTrue())
+		})
+
+		Context("with sorting", func() {
+			BeforeEach(func() {
+				options.Sort = true
+			})
+
+			It("moves out-of-order events", func() {
+				Expect(data.EventStream).To(Equal([]*cast.Event{
+					event1, event3, event2, event4, duplicate,
+				}))
+				Expect(event3.Time).To(Equal(float64(1)))
+				Expect(report.OutOfOrder).To(Equal(1))
+			})
+		})
+
+		Context("dropping duplicates", func() {
+			BeforeEach(func() {
+				options.DropDuplicates = true
+			})
+
+			It("removes them", func() {
+				Expect(data.EventStream).To(HaveLen(4))
+				Expect(data.EventStream[3]).To(BeIdenticalTo(event4))
+				Expect(report.Duplicates).To(Equal(1))
+			})
+		})
+	})
+
+	Context("with a valid event stream", func() {
+		It("changes nothing", func() {
+			report, err := editor.Fix(&cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1, Type: "o", Data: "a"},
+					{Time: 1, Type: "o", Data: "b"},
+					{Time: 2, Type: "o", Data: "b"},
+				},
+			}, editor.FixOptions{DropDuplicates: true})
+
+			Expect(err).To(Succeed())
+			Expect(report.Changed()).ToNot(BeTrue())
+		})
+	})
+})
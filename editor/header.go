@@ -0,0 +1,219 @@
+package editor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// HeaderKeys lists the keys that identify the header fields that can be
+// retrieved and modified by `GetHeader`, `SetHeader` and `UnsetHeader`.
+//
+// Nested fields are addressed with a dot (e.g., `theme.bg`).
+var HeaderKeys = []string{
+	"width",
+	"height",
+	"timestamp",
+	"command",
+	"title",
+	"idle_time_limit",
+	"theme.fg",
+	"theme.bg",
+	"theme.palette",
+	"env.SHELL",
+	"env.TERM",
+}
+
+// colorRegexp matches colors in the CSS hex format (`#rgb` or
+// `#rrggbb`), as used by asciinema themes.
+var colorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// GetHeader retrieves the value of the header field identified by `key`
+// formatted as a string.
+//
+// Unset fields have an empty value.
+func GetHeader(c *cast.Cast, key string) (value string, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	h := &c.Header
+
+	switch key {
+	case "width":
+		value = strconv.FormatUint(uint64(h.Width), 10)
+	case "height":
+		value = strconv.FormatUint(uint64(h.Height), 10)
+	case "timestamp":
+		if h.Timestamp != 0 {
+			value = strconv.FormatUint(uint64(h.Timestamp), 10)
+		}
+	case "command":
+		value = h.Command
+	case "title":
+		value = h.Title
+	case "idle_time_limit":
+		if h.IdleTimeLimit != 0 {
+			value = strconv.FormatFloat(h.IdleTimeLimit, 'f', -1, 64)
+		}
+	case "theme.fg":
+		value = h.Theme.Fg
+	case "theme.bg":
+		value = h.Theme.Bg
+	case "theme.palette":
+		value = h.Theme.Palette
+	case "env.SHELL":
+		value = h.Env.Shell
+	case "env.TERM":
+		value = h.Env.Term
+	default:
+		err = errors.Errorf("unknown header key '%s'", key)
+	}
+
+	return
+}
+
+// SetHeader validates `value` and assigns it to the header field
+// identified by `key`.
+//
+// The validation follows the asciicast v2 format:
+// - `width` and `height` must be positive integers;
+// - `timestamp` must be a unix timestamp;
+// - `idle_time_limit` must be a positive number of seconds;
+// - `theme.fg` and `theme.bg` must be CSS hex colors (e.g., `#000`); and
+// - `theme.palette` must be a list of 8 or 16 CSS hex colors separated
+//   by a colon.
+func SetHeader(c *cast.Cast, key, value string) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	var (
+		h      = &c.Header
+		number uint64
+	)
+
+	switch key {
+	case "width", "height":
+		number, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || number == 0 {
+			err = errors.Errorf(
+				"%s must be a positive integer, got '%s'", key, value)
+			return
+		}
+
+		if key == "width" {
+			h.Width = uint(number)
+		} else {
+			h.Height = uint(number)
+		}
+	case "timestamp":
+		number, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			err = errors.Errorf(
+				"timestamp must be a unix timestamp, got '%s'", value)
+			return
+		}
+
+		h.Timestamp = uint(number)
+	case "command":
+		h.Command = value
+	case "title":
+		h.Title = value
+	case "idle_time_limit":
+		var limit float64
+
+		limit, err = strconv.ParseFloat(value, 64)
+		if err != nil || limit <= 0 {
+			err = errors.Errorf(
+				"idle_time_limit must be a positive number, got '%s'", value)
+			return
+		}
+
+		h.IdleTimeLimit = limit
+	case "theme.fg", "theme.bg":
+		if !colorRegexp.MatchString(value) {
+			err = errors.Errorf(
+				"%s must be a color in the `#rrggbb` format, got '%s'",
+				key, value)
+			return
+		}
+
+		if key == "theme.fg" {
+			h.Theme.Fg = value
+		} else {
+			h.Theme.Bg = value
+		}
+	case "theme.palette":
+		colors := strings.Split(value, ":")
+		if len(colors) != 8 && len(colors) != 16 {
+			err = errors.Errorf(
+				"theme.palette must have 8 or 16 colors, got %d",
+				len(colors))
+			return
+		}
+
+		for _, color := range colors {
+			if !colorRegexp.MatchString(color) {
+				err = errors.Errorf(
+					"theme.palette colors must be in the `#rrggbb` format, got '%s'",
+					color)
+				return
+			}
+		}
+
+		h.Theme.Palette = value
+	case "env.SHELL":
+		h.Env.Shell = value
+	case "env.TERM":
+		h.Env.Term = value
+	default:
+		err = errors.Errorf("unknown header key '%s'", key)
+	}
+
+	return
+}
+
+// UnsetHeader clears the header field identified by `key`.
+//
+// Required fields (`width` and `height`) can't be unset.
+func UnsetHeader(c *cast.Cast, key string) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	h := &c.Header
+
+	switch key {
+	case "width", "height":
+		err = errors.Errorf("%s is required and can't be unset", key)
+	case "timestamp":
+		h.Timestamp = 0
+	case "command":
+		h.Command = ""
+	case "title":
+		h.Title = ""
+	case "idle_time_limit":
+		h.IdleTimeLimit = 0
+	case "theme.fg":
+		h.Theme.Fg = ""
+	case "theme.bg":
+		h.Theme.Bg = ""
+	case "theme.palette":
+		h.Theme.Palette = ""
+	case "env.SHELL":
+		h.Env.Shell = ""
+	case "env.TERM":
+		h.Env.Term = ""
+	default:
+		err = errors.Errorf("unknown header key '%s'", key)
+	}
+
+	return
+}
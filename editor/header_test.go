@@ -0,0 +1,139 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Header", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			Header: cast.Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Title:   "demo",
+			},
+		}
+	})
+
+	Describe("parameter validation", func() {
+		Context("with nil cast", func() {
+			It("fails", func() {
+				_, err := editor.GetHeader(nil, "title")
+				Expect(err).ToNot(Succeed())
+
+				err = editor.SetHeader(nil, "title", "a")
+				Expect(err).ToNot(Succeed())
+
+				err = editor.UnsetHeader(nil, "title")
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with unknown key", func() {
+			It("fails", func() {
+				_, err := editor.GetHeader(data, "foo")
+				Expect(err).ToNot(Succeed())
+
+				err = editor.SetHeader(data, "env.FOO", "a")
+				Expect(err).ToNot(Succeed())
+
+				err = editor.UnsetHeader(data, "theme")
+				Expect(err).ToNot(Succeed())
+			})
+		})
+	})
+
+	Describe("GetHeader", func() {
+		It("supports all the keys", func() {
+			for _, key := range editor.HeaderKeys {
+				_, err := editor.GetHeader(data, key)
+				Expect(err).To(Succeed(), key)
+			}
+		})
+
+		It("formats the values", func() {
+			value, err := editor.GetHeader(data, "width")
+			Expect(err).To(Succeed())
+			Expect(value).To(Equal("80"))
+
+			value, err = editor.GetHeader(data, "title")
+			Expect(err).To(Succeed())
+			Expect(value).To(Equal("demo"))
+		})
+
+		It("has empty values for unset fields", func() {
+			value, err := editor.GetHeader(data, "idle_time_limit")
+			Expect(err).To(Succeed())
+			Expect(value).To(BeEmpty())
+		})
+	})
+
+	Describe("SetHeader", func() {
+		It("sets strings", func() {
+			Expect(editor.SetHeader(data, "title", "new")).To(Succeed())
+			Expect(editor.SetHeader(data, "env.SHELL", "/bin/bash")).To(Succeed())
+
+			Expect(data.Header.Title).To(Equal("new"))
+			Expect(data.Header.Env.Shell).To(Equal("/bin/bash"))
+		})
+
+		It("sets numbers", func() {
+			Expect(editor.SetHeader(data, "width", "100")).To(Succeed())
+			Expect(editor.SetHeader(data, "idle_time_limit", "1.5")).To(Succeed())
+
+			Expect(data.Header.Width).To(Equal(uint(100)))
+			Expect(data.Header.IdleTimeLimit).To(Equal(1.5))
+		})
+
+		It("fails with invalid sizes", func() {
+			Expect(editor.SetHeader(data, "width", "0")).ToNot(Succeed())
+			Expect(editor.SetHeader(data, "height", "-1")).ToNot(Succeed())
+			Expect(editor.SetHeader(data, "height", "a")).ToNot(Succeed())
+		})
+
+		It("fails with invalid idle time limits", func() {
+			Expect(editor.SetHeader(data, "idle_time_limit", "0")).ToNot(Succeed())
+			Expect(editor.SetHeader(data, "idle_time_limit", "a")).ToNot(Succeed())
+		})
+
+		It("validates colors", func() {
+			Expect(editor.SetHeader(data, "theme.bg", "#000")).To(Succeed())
+			Expect(editor.SetHeader(data, "theme.fg", "#aabbcc")).To(Succeed())
+			Expect(editor.SetHeader(data, "theme.fg", "red")).ToNot(Succeed())
+
+			Expect(data.Header.Theme.Bg).To(Equal("#000"))
+			Expect(data.Header.Theme.Fg).To(Equal("#aabbcc"))
+		})
+
+		It("validates the palette", func() {
+			Expect(editor.SetHeader(data, "theme.palette",
+				"#000:#111:#222:#333:#444:#555:#666:#777")).To(Succeed())
+			Expect(editor.SetHeader(data, "theme.palette",
+				"#000:#111:#222")).ToNot(Succeed())
+			Expect(editor.SetHeader(data, "theme.palette",
+				"#000:#111:#222:#333:#444:#555:#666:foo")).ToNot(Succeed())
+
+			Expect(data.Header.Theme.Palette).To(Equal(
+				"#000:#111:#222:#333:#444:#555:#666:#777"))
+		})
+	})
+
+	Describe("UnsetHeader", func() {
+		It("clears the field", func() {
+			Expect(editor.UnsetHeader(data, "title")).To(Succeed())
+			Expect(data.Header.Title).To(BeEmpty())
+		})
+
+		It("fails with required fields", func() {
+			Expect(editor.UnsetHeader(data, "width")).ToNot(Succeed())
+			Expect(editor.UnsetHeader(data, "height")).ToNot(Succeed())
+		})
+	})
+})
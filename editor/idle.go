@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// IdleLimitOptions configures how `BakeIdleLimit` applies an idle time
+// limit.
+type IdleLimitOptions struct {
+	// Limit is the longest delay (in seconds) allowed. When zero, the
+	// `idle_time_limit` from the header is used.
+	Limit float64
+
+	// Keep makes the `idle_time_limit` header field be kept as it is
+	// (by default, it gets cleared).
+	Keep bool
+
+	// NewLimit, when positive, replaces the `idle_time_limit` header
+	// field once the limit has been applied.
+	NewLimit float64
+}
+
+// BakeIdleLimit applies an idle time limit to the timestamps of a cast,
+// such that the cast plays the same way regardless of whether the
+// player honors the `idle_time_limit` header field.
+//
+// Just like asciinema players do, every delay longer than the limit
+// (including the one before the first event) gets reduced to it.
+//
+// Once applied, the header field is cleared, unless it's either kept
+// or replaced by a new limit.
+func BakeIdleLimit(c *cast.Cast, options IdleLimitOptions) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if options.Keep && options.NewLimit != 0 {
+		err = errors.Errorf("header limit can't be both kept and replaced")
+		return
+	}
+
+	if options.Limit < 0 || options.NewLimit < 0 {
+		err = errors.Errorf("idle time limit must not be negative")
+		return
+	}
+
+	limit := options.Limit
+	if limit == 0 {
+		limit = c.Header.IdleTimeLimit
+	}
+
+	if limit <= 0 {
+		err = errors.Errorf("cast has no idle time limit to apply")
+		return
+	}
+
+	var (
+		prev  float64
+		shift float64
+		delay float64
+	)
+
+	for _, ev := range c.EventStream {
+		delay = ev.Time - prev
+		prev = ev.Time
+
+		if delay > limit {
+			shift += delay - limit
+		}
+
+		ev.Time -= shift
+	}
+
+	roundTimes(c.EventStream)
+
+	switch {
+	case options.NewLimit != 0:
+		c.Header.IdleTimeLimit = options.NewLimit
+	case !options.Keep:
+		c.Header.IdleTimeLimit = 0
+	}
+
+	return
+}
@@ -0,0 +1,82 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("BakeIdleLimit", func() {
+	var (
+		data *cast.Cast
+		err  error
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 3, Type: "o", Data: "$ "},
+				{Time: 4, Type: "i", Data: "l"},
+				{Time: 9, Type: "o", Data: "l"},
+				{Time: 10, Type: "o", Data: "s"},
+			},
+		}
+		data.Header.IdleTimeLimit = 2
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			err = editor.BakeIdleLimit(nil, editor.IdleLimitOptions{})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails without a limit", func() {
+			data.Header.IdleTimeLimit = 0
+
+			err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with a negative limit", func() {
+			err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{
+				Limit: -1,
+			})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails if keeping and replacing the header limit", func() {
+			err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{
+				Keep:     true,
+				NewLimit: 1,
+			})
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	It("applies the header limit and clears it", func() {
+		err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{2, 3, 5, 6}))
+		Expect(data.Header.IdleTimeLimit).To(BeZero())
+	})
+
+	It("keeps the header limit", func() {
+		err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{
+			Keep: true,
+		})
+		Expect(err).To(Succeed())
+		Expect(data.Header.IdleTimeLimit).To(Equal(2.0))
+	})
+
+	It("applies a specific limit and sets a new one", func() {
+		err = editor.BakeIdleLimit(data, editor.IdleLimitOptions{
+			Limit:    0.5,
+			NewLimit: 0.25,
+		})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0.5, 1, 1.5, 2}))
+		Expect(data.Header.IdleTimeLimit).To(Equal(0.25))
+	})
+})
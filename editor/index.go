@@ -0,0 +1,54 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// IndexRange delimits a portion of the event stream by the position of
+// its events (both ends included).
+//
+// Unlike timestamps, positions always identify a single event, even
+// when multiple events share the same timestamp.
+type IndexRange struct {
+	// From is the position of the first event.
+	From int
+	// To is the position of the last event.
+	To int
+}
+
+// Contains verifies whether the position `idx` lies within the range.
+func (r IndexRange) Contains(idx int) bool {
+	return idx >= r.From && idx <= r.To
+}
+
+// validate verifies whether the range refers to events that exist in
+// the event stream of a cast.
+func (r IndexRange) validate(c *cast.Cast) (err error) {
+	if r.From < 0 || r.To >= len(c.EventStream) {
+		err = errors.Errorf(
+			"index range %d-%d is out of the event stream bounds (0-%d)",
+			r.From, r.To, len(c.EventStream)-1)
+		return
+	}
+
+	if r.From > r.To {
+		err = errors.Errorf("`from` index cant be bigger than `to` index")
+		return
+	}
+
+	return
+}
+
+// TimeRange delimits a portion of a cast by the timestamps of its
+// events (both ends included).
+type TimeRange struct {
+	From float64
+	To   float64
+}
+
+// Contains verifies whether the whole `[from, to]` interval lies within
+// the range.
+func (r TimeRange) Contains(from, to float64) bool {
+	return from >= r.From && to <= r.To
+}
@@ -0,0 +1,125 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Insert splices the event stream of `other` into the event stream of
+// `c` at the time specified by `at`.
+//
+// The events of `other` get copied, having their timestamps offset by
+// `at`, while all the events of `c` that happen at or after `at` get
+// delayed by the duration of `other` (the timestamp of its last event).
+//
+// For instance, inserting a cast with events at
+//
+//	0  1  2
+//
+// at `2` into a cast with events at
+//
+//	1  2  3
+//
+// results in:
+//
+//	1 [2  3  4] 4  5
+//
+// If the terminal size of `other` differs from the size of `c` at
+// `at`, `policy` determines how they get reconciled (see `SizePolicy`).
+// Regardless of the policy, if the inserted events leave the terminal
+// at a different size (e.g., because `other` has resize events), a
+// resize event restores the size of `c` after them.
+//
+// It assumes that both casts are entirely valid (see
+// `github.com/cirocosta/asciinema-edit/cast#Validate`).
+func Insert(c, other *cast.Cast, at float64, policy SizePolicy) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if other == nil {
+		err = errors.Errorf("cast to insert must not be nil")
+		return
+	}
+
+	if len(other.EventStream) == 0 {
+		err = errors.Errorf("cast to insert must have a non-empty event stream")
+		return
+	}
+
+	if at < 0 {
+		err = errors.Errorf("`at` must not be negative")
+		return
+	}
+
+	var (
+		idx           = len(c.EventStream)
+		duration      = other.EventStream[len(other.EventStream)-1].Time
+		inserted      = make([]*cast.Event, 0, len(other.EventStream)+2)
+		width, height uint
+	)
+
+	for i, ev := range c.EventStream {
+		if ev.Time >= at {
+			idx = i
+			break
+		}
+	}
+
+	width, height = sizeAt(c, idx)
+
+	if width != other.Header.Width || height != other.Header.Height {
+		switch policy {
+		case SizeFail:
+			err = errors.Errorf(
+				"size mismatch: %dx%d at %g, cast to insert is %dx%d",
+				width, height, at,
+				other.Header.Width, other.Header.Height)
+			return
+		case SizeMax:
+			c.Header.Width = maxUint(c.Header.Width, other.Header.Width)
+			c.Header.Height = maxUint(c.Header.Height, other.Header.Height)
+		case SizeResize:
+			inserted = append(inserted, resizeEvent(at,
+				other.Header.Width, other.Header.Height))
+		default:
+			err = errors.Errorf("unknown size policy '%s'", policy)
+			return
+		}
+	}
+
+	for _, ev := range other.EventStream {
+		inserted = append(inserted, &cast.Event{
+			Time: ev.Time + at,
+			Type: ev.Type,
+			Data: ev.Data,
+		})
+	}
+
+	// the events of `c` that follow must be played at the size they
+	// were recorded at, even if the inserted events resize the
+	// terminal.
+	width, height = sizeAt(c, idx)
+
+	played := &cast.Cast{
+		Header:      cast.Header{Width: width, Height: height},
+		EventStream: inserted,
+	}
+
+	finalWidth, finalHeight := sizeAt(played, len(inserted))
+	if finalWidth != width || finalHeight != height {
+		inserted = append(inserted, resizeEvent(at+duration, width, height))
+	}
+
+	for _, ev := range c.EventStream[idx:] {
+		ev.Time += duration
+	}
+
+	c.EventStream = append(
+		c.EventStream[:idx],
+		append(inserted, c.EventStream[idx:]...)...)
+
+	roundTimes(c.EventStream)
+	return
+}
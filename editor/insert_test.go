@@ -0,0 +1,137 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Insert", func() {
+	var data, other *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 80, Height: 24},
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+				{Time: 3, Type: "o", Data: "c"},
+			},
+		}
+
+		other = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 80, Height: 24},
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "o", Data: "x"},
+				{Time: 1, Type: "o", Data: "y"},
+				{Time: 2, Type: "o", Data: "z"},
+			},
+		}
+	})
+
+	Describe("parameter validation", func() {
+		It("fails with nil casts", func() {
+			Expect(editor.Insert(nil, other, 1, editor.SizeFail)).ToNot(Succeed())
+			Expect(editor.Insert(data, nil, 1, editor.SizeFail)).ToNot(Succeed())
+		})
+
+		It("fails with empty cast to insert", func() {
+			other.EventStream = []*cast.Event{}
+			Expect(editor.Insert(data, other, 1, editor.SizeFail)).ToNot(Succeed())
+		})
+
+		It("fails with negative time", func() {
+			Expect(editor.Insert(data, other, -1, editor.SizeFail)).ToNot(Succeed())
+		})
+	})
+
+	Context("with casts of the same size", func() {
+		It("inserts the events shifting the subsequent ones", func() {
+			Expect(editor.Insert(data, other, 2, editor.SizeFail)).To(Succeed())
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "x"},
+				{Time: 3, Type: "o", Data: "y"},
+				{Time: 4, Type: "o", Data: "z"},
+				{Time: 4, Type: "o", Data: "b"},
+				{Time: 5, Type: "o", Data: "c"},
+			}))
+		})
+
+		It("appends if inserting after the last event", func() {
+			Expect(editor.Insert(data, other, 10, editor.SizeFail)).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(6))
+			Expect(data.EventStream[3].Time).To(Equal(float64(10)))
+			Expect(data.EventStream[5].Time).To(Equal(float64(12)))
+		})
+
+		It("restores the size if the inserted cast resizes the terminal", func() {
+			other.EventStream[1] = &cast.Event{Time: 1, Type: "r", Data: "120x40"}
+
+			Expect(editor.Insert(data, other, 2, editor.SizeFail)).To(Succeed())
+			Expect(data.Header.Width).To(Equal(uint(80)))
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "x"},
+				{Time: 3, Type: "r", Data: "120x40"},
+				{Time: 4, Type: "o", Data: "z"},
+				{Time: 4, Type: "r", Data: "80x24"},
+				{Time: 4, Type: "o", Data: "b"},
+				{Time: 5, Type: "o", Data: "c"},
+			}))
+		})
+
+		It("doesn't share events with the inserted cast", func() {
+			Expect(editor.Insert(data, other, 2, editor.SizeFail)).To(Succeed())
+			Expect(other.EventStream[0].Time).To(Equal(float64(0)))
+		})
+	})
+
+	Context("with casts of different sizes", func() {
+		BeforeEach(func() {
+			other.Header.Width = 100
+			other.Header.Height = 20
+		})
+
+		It("fails with the fail policy", func() {
+			Expect(editor.Insert(data, other, 2, editor.SizeFail)).ToNot(Succeed())
+		})
+
+		It("takes the biggest dimensions with the max policy", func() {
+			Expect(editor.Insert(data, other, 2, editor.SizeMax)).To(Succeed())
+			Expect(data.Header.Width).To(Equal(uint(100)))
+			Expect(data.Header.Height).To(Equal(uint(24)))
+			Expect(data.EventStream).To(HaveLen(6))
+		})
+
+		It("emits resize events with the resize policy", func() {
+			Expect(editor.Insert(data, other, 2, editor.SizeResize)).To(Succeed())
+			Expect(data.Header.Width).To(Equal(uint(80)))
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "r", Data: "100x20"},
+				{Time: 2, Type: "o", Data: "x"},
+				{Time: 3, Type: "o", Data: "y"},
+				{Time: 4, Type: "o", Data: "z"},
+				{Time: 4, Type: "r", Data: "80x24"},
+				{Time: 4, Type: "o", Data: "b"},
+				{Time: 5, Type: "o", Data: "c"},
+			}))
+		})
+
+		It("restores the biggest dimensions with the max policy", func() {
+			other.EventStream[1] = &cast.Event{Time: 1, Type: "r", Data: "120x40"}
+
+			Expect(editor.Insert(data, other, 2, editor.SizeMax)).To(Succeed())
+			Expect(data.EventStream[4]).To(Equal(
+				&cast.Event{Time: 4, Type: "r", Data: "100x24"}))
+		})
+
+		It("considers previous resize events", func() {
+			data.EventStream[0] = &cast.Event{Time: 1, Type: "r", Data: "100x20"}
+			Expect(editor.Insert(data, other, 2, editor.SizeFail)).To(Succeed())
+		})
+	})
+})
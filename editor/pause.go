@@ -0,0 +1,104 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Pause inserts a pause of `duration` seconds right before each of the
+// timestamps in `at`, shifting all of the events that happen at or
+// after such timestamps.
+//
+// Timestamps refer to the original cast, thus pausing at `1` and `2`
+// delays the events at `2` by twice the duration.
+func Pause(c *cast.Cast, at []float64, duration float64) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if len(at) == 0 {
+		err = errors.Errorf("at least one timestamp must be specified")
+		return
+	}
+
+	if duration <= 0 {
+		err = errors.Errorf("duration must be positive")
+		return
+	}
+
+	last := c.EventStream[len(c.EventStream)-1].Time
+
+	for _, point := range at {
+		if point < 0 || point > last {
+			err = errors.Errorf(
+				"timestamp %g is out of the cast bounds (0 to %g)",
+				point, last)
+			return
+		}
+	}
+
+	for _, ev := range c.EventStream {
+		var shift float64
+
+		for _, point := range at {
+			if ev.Time >= point {
+				shift += duration
+			}
+		}
+
+		ev.Time += shift
+	}
+
+	roundTimes(c.EventStream)
+	return
+}
+
+// Hold keeps the final frame of a cast on the screen for `duration`
+// seconds.
+//
+// Given that the duration of a cast is determined by its last event,
+// an output event that prints nothing is appended to carry the time.
+func Hold(c *cast.Cast, duration float64) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if duration <= 0 {
+		err = errors.Errorf("duration must be positive")
+		return
+	}
+
+	c.EventStream = append(c.EventStream, &cast.Event{
+		Time: RoundTime(c.EventStream[len(c.EventStream)-1].Time + duration),
+		Type: "o",
+		Data: "",
+	})
+
+	return
+}
+
+// MarkerTime retrieves the timestamp of the first marker event (`m`)
+// labeled `label`.
+func MarkerTime(c *cast.Cast, label string) (time float64, err error) {
+	for _, ev := range c.EventStream {
+		if ev.Type == "m" && ev.Data == label {
+			time = ev.Time
+			return
+		}
+	}
+
+	err = errors.Errorf("couldn't find marker '%s'", label)
+	return
+}
@@ -0,0 +1,117 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Pause", func() {
+	var (
+		data *cast.Cast
+		err  error
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "$ "},
+				{Time: 2, Type: "m", Data: "demo"},
+				{Time: 3, Type: "o", Data: "ls"},
+				{Time: 4, Type: "o", Data: "file"},
+			},
+		}
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			err = editor.Pause(nil, []float64{1}, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails without timestamps", func() {
+			err = editor.Pause(data, nil, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with non-positive duration", func() {
+			err = editor.Pause(data, []float64{1}, 0)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with timestamps after the end", func() {
+			err = editor.Pause(data, []float64{5}, 1)
+			Expect(err).ToNot(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 3, 4}))
+		})
+	})
+
+	It("shifts the events at and after the timestamp", func() {
+		err = editor.Pause(data, []float64{2.5}, 10)
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{1, 2, 13, 14}))
+
+		err = editor.Pause(data, []float64{2}, 1)
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{1, 3, 14, 15}))
+	})
+
+	It("pauses at multiple timestamps of the original cast", func() {
+		err = editor.Pause(data, []float64{3, 1}, 2)
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{3, 4, 7, 8}))
+	})
+})
+
+var _ = Describe("Hold", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "$ "},
+			},
+		}
+	})
+
+	It("fails with non-positive duration", func() {
+		Expect(editor.Hold(data, -1)).ToNot(Succeed())
+	})
+
+	It("appends a no-op event", func() {
+		Expect(editor.Hold(data, 5)).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(2))
+		Expect(*data.EventStream[1]).To(Equal(cast.Event{
+			Time: 6, Type: "o", Data: "",
+		}))
+	})
+
+	It("rounds the time of the appended event", func() {
+		data.EventStream[0].Time = 0.1
+		Expect(editor.Hold(data, 0.2)).To(Succeed())
+		Expect(data.EventStream[1].Time).To(Equal(0.3))
+	})
+})
+
+var _ = Describe("MarkerTime", func() {
+	var data = &cast.Cast{
+		EventStream: []*cast.Event{
+			{Time: 1, Type: "o", Data: "intro"},
+			{Time: 2, Type: "m", Data: "intro"},
+			{Time: 3, Type: "m", Data: "intro"},
+		},
+	}
+
+	It("finds the first marker with the label", func() {
+		time, err := editor.MarkerTime(data, "intro")
+		Expect(err).To(Succeed())
+		Expect(time).To(Equal(2.0))
+	})
+
+	It("fails if there's no such marker", func() {
+		_, err := editor.MarkerTime(data, "outro")
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -1,7 +1,12 @@
 package editor
 
 import (
+	"fmt"
+	"math"
+	"sort"
+
 	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/stats"
 	"github.com/pkg/errors"
 )
 
@@ -22,10 +27,118 @@ func (q *QuantizeRange) InRange(value float64) bool {
 	return value >= q.From && value < q.To
 }
 
-// Overlaps verifies whether a given range (`another`) overlaps with
-// this range.
+// RangeOverlaps verifies whether a given range (`another`) shares any
+// value with this range.
+//
+// Given that ranges don't include their upper bound, ranges that only
+// touch each other (e.g., `[1,2)` and `[2,3)`) don't overlap.
 func (q *QuantizeRange) RangeOverlaps(another QuantizeRange) bool {
-	return q.InRange(another.From) || q.InRange(another.To)
+	return q.From < another.To && another.From < q.To
+}
+
+// String formats the range using the interval notation (e.g.,
+// `[1, 2)` or `[2, +inf)`).
+func (q QuantizeRange) String() string {
+	if q.To == math.MaxFloat64 {
+		return fmt.Sprintf("[%g, +inf)", q.From)
+	}
+
+	return fmt.Sprintf("[%g, %g)", q.From, q.To)
+}
+
+// OverlapPolicy determines what to do with overlapping quantization
+// ranges.
+type OverlapPolicy string
+
+const (
+	// OverlapFail rejects overlapping ranges.
+	OverlapFail OverlapPolicy = "fail"
+	// OverlapMerge joins overlapping ranges into a single one that
+	// covers all of them.
+	OverlapMerge OverlapPolicy = "merge"
+)
+
+// ParseOverlapPolicy converts a string into an OverlapPolicy.
+func ParseOverlapPolicy(input string) (policy OverlapPolicy, err error) {
+	policy = OverlapPolicy(input)
+
+	switch policy {
+	case OverlapFail, OverlapMerge:
+	default:
+		err = errors.Errorf(
+			"unknown overlap policy '%s' (must be fail or merge)", input)
+	}
+
+	return
+}
+
+// NormalizeQuantizeRanges sorts a list of quantization ranges by their
+// lower bound, dealing with the ones that overlap according to a
+// policy.
+//
+// With `OverlapFail`, the first pair of overlapping ranges makes it
+// fail, while with `OverlapMerge` the overlapping ranges are replaced
+// by their union (quantizing to the smallest lower bound).
+func NormalizeQuantizeRanges(ranges []QuantizeRange, policy OverlapPolicy) (res []QuantizeRange, err error) {
+	var sorted = make([]QuantizeRange, len(ranges))
+
+	copy(sorted, ranges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].From < sorted[j].From
+	})
+
+	res = make([]QuantizeRange, 0, len(sorted))
+
+	for _, qRange := range sorted {
+		if qRange.From >= qRange.To {
+			err = errors.Errorf("range %s is empty", qRange)
+			res = nil
+			return
+		}
+
+		last := len(res) - 1
+		if last < 0 || !res[last].RangeOverlaps(qRange) {
+			res = append(res, qRange)
+			continue
+		}
+
+		switch policy {
+		case OverlapMerge:
+			if qRange.To > res[last].To {
+				res[last].To = qRange.To
+			}
+		case OverlapFail:
+			err = errors.Errorf("ranges %s and %s overlap", res[last], qRange)
+			res = nil
+			return
+		default:
+			err = errors.Errorf("unknown overlap policy '%s'", policy)
+			res = nil
+			return
+		}
+	}
+
+	return
+}
+
+// QuantizeGaps retrieves the intervals between the lowest and the
+// highest bounds of a list of non-overlapping ranges (sorted by their
+// lower bound) that are not covered by any of them.
+//
+// Delays that fall in a gap are left untouched by the quantization.
+func QuantizeGaps(ranges []QuantizeRange) (gaps []QuantizeRange) {
+	gaps = make([]QuantizeRange, 0)
+
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].From > ranges[i-1].To {
+			gaps = append(gaps, QuantizeRange{
+				From: ranges[i-1].To,
+				To:   ranges[i].From,
+			})
+		}
+	}
+
+	return
 }
 
 // Quantize constraints a set of inputs that lie in a range to a single
@@ -56,6 +169,53 @@ func (q *QuantizeRange) RangeOverlaps(another QuantizeRange) bool {
 //    the quantization range).
 // 4. adjust the rest of the event stream.
 func Quantize(c *cast.Cast, ranges []QuantizeRange) (err error) {
+	err = QuantizeScoped(c, ranges, Selection{})
+	return
+}
+
+// QuantizeScoped performs the same quantization as `Quantize`, but only
+// on the selected delays (see `Selection.Delays`), leaving all the
+// other delays untouched.
+//
+// Time ranges refer to the timestamps of the original cast.
+func QuantizeScoped(c *cast.Cast, ranges []QuantizeRange, selection Selection) (err error) {
+	var decisions []QuantizeDecision
+
+	decisions, err = ExplainQuantize(c, ranges, selection)
+	if err != nil {
+		return
+	}
+
+	for i, decision := range decisions {
+		c.EventStream[i+1].Time = c.EventStream[i].Time + decision.Quantized
+	}
+
+	roundTimes(c.EventStream)
+	return
+}
+
+// QuantizeDecision describes what happens to the delay that precedes
+// the event at position `Index` when quantizing.
+type QuantizeDecision struct {
+	// Index is the position of the event that the delay precedes.
+	Index int
+	// Time is the original timestamp of such event.
+	Time float64
+	// Delay is the original delay.
+	Delay float64
+	// Quantized is the delay after quantization.
+	Quantized float64
+	// InScope indicates whether the delay is selected.
+	InScope bool
+	// Range is the range that the delay fell into (nil if none).
+	Range *QuantizeRange
+}
+
+// ExplainQuantize computes what `QuantizeScoped` would do to each
+// delay of a cast, without modifying it.
+//
+// Overlapping ranges are rejected (see `NormalizeQuantizeRanges`).
+func ExplainQuantize(c *cast.Cast, ranges []QuantizeRange, selection Selection) (decisions []QuantizeDecision, err error) {
 	if c == nil {
 		err = errors.Errorf("cast must not be nil")
 		return
@@ -71,29 +231,102 @@ func Quantize(c *cast.Cast, ranges []QuantizeRange) (err error) {
 		return
 	}
 
-	var (
-		deltas = make([]float64, len(c.EventStream))
-		delta  float64
-		i      int
-	)
+	_, err = NormalizeQuantizeRanges(ranges, OverlapFail)
+	if err != nil {
+		return
+	}
+
+	var delays []bool
+
+	delays, err = selection.Delays(c)
+	if err != nil {
+		return
+	}
+
+	decisions = make([]QuantizeDecision, len(c.EventStream)-1)
+
+	for i := 0; i < len(c.EventStream)-1; i++ {
+		decision := &decisions[i]
 
-	for i = 0; i < len(c.EventStream)-1; i++ {
-		delta = c.EventStream[i+1].Time - c.EventStream[i].Time
+		decision.Index = i + 1
+		decision.Time = c.EventStream[i+1].Time
+		decision.Delay = c.EventStream[i+1].Time - c.EventStream[i].Time
+		decision.Quantized = decision.Delay
+		decision.InScope = delays[i+1]
 
-		for _, qRange := range ranges {
-			if !qRange.InRange(delta) {
+		if !decision.InScope {
+			continue
+		}
+
+		for idx := range ranges {
+			if !ranges[idx].InRange(decision.Delay) {
 				continue
 			}
 
-			delta = qRange.From
+			decision.Range = &ranges[idx]
+			decision.Quantized = ranges[idx].From
 			break
 		}
+	}
+
+	return
+}
+
+// AdaptiveOptions configures how `AdaptiveRange` derives a quantization
+// range from the delays of a cast.
+type AdaptiveOptions struct {
+	// Percentile (from 0 to 100) of the delays that the longer
+	// delays get capped at (e.g., 95).
+	Percentile float64
+	// MaxIdle is the longest delay (in seconds) allowed.
+	MaxIdle float64
+}
+
+// AdaptiveRange derives a quantization range from the distribution of
+// the delays of a cast, capping all of the delays above a given
+// percentile (or above a maximum idle time) to such value.
+//
+// When both the percentile and the maximum idle time are specified,
+// the smallest of the two caps is used. The cap gets rounded just like
+// timestamps are (see `RoundTime`).
+func AdaptiveRange(c *cast.Cast, options AdaptiveOptions) (res QuantizeRange, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if options.Percentile == 0 && options.MaxIdle == 0 {
+		err = errors.Errorf("either a percentile or a maximum idle time must be specified")
+		return
+	}
+
+	if options.MaxIdle < 0 {
+		err = errors.Errorf("maximum idle time must not be negative")
+		return
+	}
+
+	res.From = options.MaxIdle
+	res.To = math.MaxFloat64
+
+	if options.Percentile != 0 {
+		var value float64
+
+		value, err = stats.Percentile(stats.Delays(c), options.Percentile)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to compute delay percentile")
+			return
+		}
 
-		deltas[i] = delta
+		if options.MaxIdle == 0 || value < res.From {
+			res.From = value
+		}
 	}
 
-	for i = 0; i < len(c.EventStream)-1; i++ {
-		c.EventStream[i+1].Time = c.EventStream[i].Time + deltas[i]
+	res.From = RoundTime(res.From)
+
+	if res.From <= 0 {
+		err = errors.Errorf("delays can't be capped at zero")
+		return
 	}
 
 	return
@@ -1,6 +1,8 @@
 package editor_test
 
 import (
+	"math"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -53,6 +55,16 @@ var _ = Describe("Quantize", func() {
 				Expect(err).ToNot(Succeed())
 			})
 		})
+
+		Context("with overlapping ranges", func() {
+			It("fails", func() {
+				err := editor.Quantize(data, []editor.QuantizeRange{
+					{From: 1, To: 3},
+					{From: 2, To: 4},
+				})
+				Expect(err).ToNot(Succeed())
+			})
+		})
 	})
 
 	Describe("RangeOverlaps", func() {
@@ -85,6 +97,31 @@ var _ = Describe("Quantize", func() {
 				To:   1.5,
 			})).To(BeTrue())
 		})
+
+		It("overlaps if it contains another range", func() {
+			Expect(qRange.RangeOverlaps(editor.QuantizeRange{
+				From: 1.2,
+				To:   1.5,
+			})).To(BeTrue())
+		})
+
+		It("overlaps if contained by another range", func() {
+			Expect(qRange.RangeOverlaps(editor.QuantizeRange{
+				From: 0,
+				To:   10,
+			})).To(BeTrue())
+		})
+
+		It("doesnt overlap if only touching another range", func() {
+			Expect(qRange.RangeOverlaps(editor.QuantizeRange{
+				From: 2,
+				To:   3,
+			})).ToNot(BeTrue())
+			Expect(qRange.RangeOverlaps(editor.QuantizeRange{
+				From: 0,
+				To:   1,
+			})).ToNot(BeTrue())
+		})
 	})
 
 	Describe("InRange", func() {
@@ -172,3 +209,282 @@ var _ = Describe("Quantize", func() {
 		})
 	})
 })
+
+var _ = Describe("AdaptiveRange", func() {
+	var (
+		data   *cast.Cast
+		qRange editor.QuantizeRange
+		err    error
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{EventStream: []*cast.Event{}}
+
+		for _, t := range []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 19} {
+			data.EventStream = append(data.EventStream, &cast.Event{
+				Time: t,
+				Type: "o",
+			})
+		}
+	})
+
+	It("fails with nil cast", func() {
+		_, err = editor.AdaptiveRange(nil, editor.AdaptiveOptions{MaxIdle: 1})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails without percentile and maximum idle time", func() {
+		_, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails with an invalid percentile", func() {
+		_, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 101,
+		})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails if the cap would be zero", func() {
+		data.EventStream[1].Time = 0
+
+		_, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 10,
+		})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("caps delays at the percentile", func() {
+		qRange, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 90,
+		})
+		Expect(err).To(Succeed())
+		Expect(qRange.From).To(Equal(1.0))
+		Expect(qRange.InRange(10)).To(BeTrue())
+	})
+
+	It("caps delays at the maximum idle time", func() {
+		qRange, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			MaxIdle: 2,
+		})
+		Expect(err).To(Succeed())
+		Expect(qRange.From).To(Equal(2.0))
+	})
+
+	It("uses the smallest cap", func() {
+		qRange, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 100,
+			MaxIdle:    2,
+		})
+		Expect(err).To(Succeed())
+		Expect(qRange.From).To(Equal(2.0))
+
+		qRange, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 90,
+			MaxIdle:    2,
+		})
+		Expect(err).To(Succeed())
+		Expect(qRange.From).To(Equal(1.0))
+	})
+
+	It("rounds the cap", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 0.1, Type: "o"},
+			{Time: 0.3, Type: "o"},
+		}
+
+		qRange, err = editor.AdaptiveRange(data, editor.AdaptiveOptions{
+			Percentile: 100,
+		})
+		Expect(err).To(Succeed())
+		Expect(qRange.From).To(Equal(0.2))
+	})
+})
+
+var _ = Describe("QuantizeScoped", func() {
+	var (
+		data   *cast.Cast
+		ranges = []editor.QuantizeRange{{From: 2, To: math.MaxFloat64}}
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "o", Data: "$ "},
+				{Time: 1, Type: "i", Data: "l"},
+				{Time: 4, Type: "i", Data: "s"},
+				{Time: 9, Type: "o", Data: "file"},
+				{Time: 10, Type: "i", Data: "e"},
+				{Time: 15, Type: "o", Data: "bye"},
+			},
+		}
+	})
+
+	It("quantizes everything with an empty selection", func() {
+		err := editor.QuantizeScoped(data, ranges, editor.Selection{})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0, 1, 3, 5, 6, 8}))
+	})
+
+	It("quantizes only the delays preceding input events", func() {
+		err := editor.QuantizeScoped(data, ranges, editor.Selection{
+			Types: []string{"i"},
+		})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0, 1, 3, 8, 9, 14}))
+	})
+
+	It("quantizes only the delays preceding output events", func() {
+		err := editor.QuantizeScoped(data, ranges, editor.Selection{
+			Types: []string{"o"},
+		})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0, 1, 4, 6, 7, 9}))
+	})
+
+	It("quantizes only the delays within the time ranges", func() {
+		err := editor.QuantizeScoped(data, ranges, editor.Selection{
+			Times: []editor.TimeRange{{From: 4, To: 10}},
+		})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0, 1, 4, 6, 7, 12}))
+	})
+
+	It("combines time ranges and types", func() {
+		err := editor.QuantizeScoped(data, ranges, editor.Selection{
+			Times: []editor.TimeRange{{From: 4, To: 15}},
+			Types: []string{"o"},
+		})
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{0, 1, 4, 6, 7, 9}))
+	})
+})
+
+var _ = Describe("NormalizeQuantizeRanges", func() {
+	var (
+		ranges []editor.QuantizeRange
+		res    []editor.QuantizeRange
+		err    error
+	)
+
+	BeforeEach(func() {
+		ranges = []editor.QuantizeRange{
+			{From: 2, To: 4},
+			{From: 5, To: math.MaxFloat64},
+			{From: 1, To: 3},
+		}
+	})
+
+	It("sorts non-overlapping ranges", func() {
+		res, err = editor.NormalizeQuantizeRanges(ranges[:2], editor.OverlapFail)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal([]editor.QuantizeRange{
+			{From: 2, To: 4},
+			{From: 5, To: math.MaxFloat64},
+		}))
+
+		res, err = editor.NormalizeQuantizeRanges(ranges[1:], editor.OverlapFail)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal([]editor.QuantizeRange{
+			{From: 1, To: 3},
+			{From: 5, To: math.MaxFloat64},
+		}))
+	})
+
+	It("rejects overlaps with the fail policy", func() {
+		_, err = editor.NormalizeQuantizeRanges(ranges, editor.OverlapFail)
+		Expect(err).To(MatchError("ranges [1, 3) and [2, 4) overlap"))
+	})
+
+	It("merges overlaps with the merge policy", func() {
+		res, err = editor.NormalizeQuantizeRanges(ranges, editor.OverlapMerge)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal([]editor.QuantizeRange{
+			{From: 1, To: 4},
+			{From: 5, To: math.MaxFloat64},
+		}))
+	})
+
+	It("rejects empty ranges", func() {
+		_, err = editor.NormalizeQuantizeRanges([]editor.QuantizeRange{
+			{From: 2, To: 2},
+		}, editor.OverlapMerge)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails with an unknown policy", func() {
+		_, err = editor.NormalizeQuantizeRanges(ranges, "ignore")
+		Expect(err).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("QuantizeGaps", func() {
+	It("finds the intervals between ranges", func() {
+		Expect(editor.QuantizeGaps([]editor.QuantizeRange{
+			{From: 0.3, To: 1},
+			{From: 2, To: 3},
+			{From: 3, To: math.MaxFloat64},
+		})).To(Equal([]editor.QuantizeRange{
+			{From: 1, To: 2},
+		}))
+	})
+})
+
+var _ = Describe("ExplainQuantize", func() {
+	It("describes what happens to each delay", func() {
+		data := &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o"},
+				{Time: 2, Type: "i"},
+				{Time: 5, Type: "o"},
+				{Time: 6.5, Type: "i"},
+			},
+		}
+		ranges := []editor.QuantizeRange{
+			{From: 2, To: math.MaxFloat64},
+		}
+
+		decisions, err := editor.ExplainQuantize(data, ranges,
+			editor.Selection{Types: []string{"o"}})
+		Expect(err).To(Succeed())
+		Expect(decisions).To(HaveLen(3))
+
+		Expect(decisions[0].Index).To(Equal(1))
+		Expect(decisions[0].InScope).To(BeFalse())
+		Expect(decisions[0].Range).To(BeNil())
+		Expect(decisions[0].Quantized).To(Equal(1.0))
+
+		Expect(decisions[1].Index).To(Equal(2))
+		Expect(decisions[1].Time).To(Equal(5.0))
+		Expect(decisions[1].Delay).To(Equal(3.0))
+		Expect(decisions[1].InScope).To(BeTrue())
+		Expect(decisions[1].Range).To(Equal(&ranges[0]))
+		Expect(decisions[1].Quantized).To(Equal(2.0))
+
+		Expect(decisions[2].InScope).To(BeFalse())
+		Expect(data.EventStream[2].Time).To(Equal(5.0))
+	})
+})
+
+var _ = Describe("QuantizeScoped with indexes", func() {
+	It("quantizes only the delays between the events in the ranges", func() {
+		data := &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "o", Data: "a"},
+				{Time: 5, Type: "o", Data: "b"},
+				{Time: 10, Type: "o", Data: "c"},
+				{Time: 15, Type: "o", Data: "d"},
+			},
+		}
+
+		err := editor.QuantizeScoped(data,
+			[]editor.QuantizeRange{{From: 1, To: math.MaxFloat64}},
+			editor.Selection{
+				Indexes: []editor.IndexRange{{From: 1, To: 2}},
+			})
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[1].Time).To(Equal(5.0))
+		Expect(data.EventStream[2].Time).To(Equal(6.0))
+		Expect(data.EventStream[3].Time).To(Equal(11.0))
+	})
+})
@@ -0,0 +1,150 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/terminal"
+	"github.com/pkg/errors"
+)
+
+// terminalReset is the escape sequence (RIS) that brings a terminal
+// back to its initial state.
+const terminalReset = "\x1bc"
+
+// Repeat plays the segment of a cast that goes from `from` (included)
+// to `to` (excluded) `times` times in a row, delaying the events that
+// follow it accordingly.
+//
+// Given that each repetition must start from the same terminal state
+// as the original segment did, if the segment doesn't leave the
+// terminal as it found it, every copy is prefixed with an output event
+// that resets the terminal and replays the output that preceded the
+// segment (see `outputBefore`), as well as with a resize event if the
+// size changed during the segment.
+func Repeat(c *cast.Cast, from, to float64, times int) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if from < 0 || from >= to {
+		err = errors.Errorf("range must satisfy 0 <= from < to")
+		return
+	}
+
+	if times < 1 {
+		err = errors.Errorf("times must be at least 1")
+		return
+	}
+
+	var (
+		start = 0
+		end   int
+	)
+
+	for start < len(c.EventStream) && c.EventStream[start].Time < from {
+		start++
+	}
+
+	end = start
+	for end < len(c.EventStream) && c.EventStream[end].Time < to {
+		end++
+	}
+
+	if start == end {
+		err = errors.Errorf("no events between %g and %g", from, to)
+		return
+	}
+
+	if times == 1 {
+		return
+	}
+
+	var (
+		duration                = to - from
+		segment                 = c.EventStream[start:end]
+		restore                 = !keepsState(c, start, end)
+		state                   string
+		width, height           = sizeAt(c, start)
+		finalWidth, finalHeight = sizeAt(c, end)
+		res                     = make([]*cast.Event, 0, len(c.EventStream))
+	)
+
+	if restore {
+		state = terminalReset + outputBefore(c, start)
+	}
+
+	res = append(res, c.EventStream[:end]...)
+
+	for n := 1; n < times; n++ {
+		offset := float64(n) * duration
+
+		if width != finalWidth || height != finalHeight {
+			res = append(res, resizeEvent(from+offset, width, height))
+		}
+
+		if restore {
+			res = append(res, &cast.Event{
+				Time: from + offset,
+				Type: "o",
+				Data: state,
+			})
+		}
+
+		for _, ev := range segment {
+			res = append(res, &cast.Event{
+				Time: ev.Time + offset,
+				Type: ev.Type,
+				Data: ev.Data,
+			})
+		}
+	}
+
+	for _, ev := range c.EventStream[end:] {
+		ev.Time += float64(times-1) * duration
+		res = append(res, ev)
+	}
+
+	c.EventStream = res
+	roundTimes(c.EventStream)
+	return
+}
+
+// keepsState verifies whether playing the events in `[start, end)`
+// leaves the terminal in the same state it had before them, in which
+// case the segment can be played again without restoring the screen.
+//
+// Segments with sequences that can't be interpreted are assumed to
+// change the state.
+func keepsState(c *cast.Cast, start, end int) bool {
+	if c.Header.Width == 0 || c.Header.Height == 0 {
+		return false
+	}
+
+	var (
+		term   = terminal.New(int(c.Header.Width), int(c.Header.Height))
+		before *terminal.Terminal
+	)
+
+	for idx, ev := range c.EventStream[:end] {
+		if idx == start {
+			before = term.Clone()
+		}
+
+		switch ev.Type {
+		case "o":
+			term.Write(ev.Data)
+		case "r":
+			width, height, err := cast.ParseSize(ev.Data)
+			if err == nil {
+				term.Resize(int(width), int(height))
+			}
+		}
+	}
+
+	return term.Unhandled() == before.Unhandled() && term.Equal(before)
+}
@@ -0,0 +1,124 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Repeat", func() {
+	var (
+		data *cast.Cast
+		err  error
+	)
+
+	events := func() (res []cast.Event) {
+		for _, ev := range data.EventStream {
+			res = append(res, *ev)
+		}
+		return
+	}
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			Header: cast.Header{Width: 80, Height: 24},
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "$ "},
+				{Time: 2, Type: "o", Data: "load: 1"},
+				{Time: 3, Type: "o", Data: "\rload: 2"},
+				{Time: 4, Type: "o", Data: "\n$ "},
+			},
+		}
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			err = editor.Repeat(nil, 1, 2, 2)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with from >= to", func() {
+			err = editor.Repeat(data, 2, 2, 2)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with less than one time", func() {
+			err = editor.Repeat(data, 1, 2, 0)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails if there are no events in the range", func() {
+			err = editor.Repeat(data, 1.2, 1.8, 2)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	It("does nothing when repeating once", func() {
+		err = editor.Repeat(data, 2, 4, 1)
+		Expect(err).To(Succeed())
+		Expect(data.EventStream).To(HaveLen(4))
+	})
+
+	It("repeats the segment restoring the terminal state", func() {
+		err = editor.Repeat(data, 2, 4, 3)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 1, Type: "o", Data: "$ "},
+			{Time: 2, Type: "o", Data: "load: 1"},
+			{Time: 3, Type: "o", Data: "\rload: 2"},
+			{Time: 4, Type: "o", Data: "\x1bc$ "},
+			{Time: 4, Type: "o", Data: "load: 1"},
+			{Time: 5, Type: "o", Data: "\rload: 2"},
+			{Time: 6, Type: "o", Data: "\x1bc$ "},
+			{Time: 6, Type: "o", Data: "load: 1"},
+			{Time: 7, Type: "o", Data: "\rload: 2"},
+			{Time: 8, Type: "o", Data: "\n$ "},
+		}))
+	})
+
+	It("repeats only the segment when it leaves the terminal as it found it", func() {
+		data.EventStream[1].Data = "x"
+		data.EventStream[2].Data = "\b\x1b[K"
+
+		err = editor.Repeat(data, 2, 4, 3)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 1, Type: "o", Data: "$ "},
+			{Time: 2, Type: "o", Data: "x"},
+			{Time: 3, Type: "o", Data: "\b\x1b[K"},
+			{Time: 4, Type: "o", Data: "x"},
+			{Time: 5, Type: "o", Data: "\b\x1b[K"},
+			{Time: 6, Type: "o", Data: "x"},
+			{Time: 7, Type: "o", Data: "\b\x1b[K"},
+			{Time: 8, Type: "o", Data: "\n$ "},
+		}))
+	})
+
+	It("restores the screen from the last time it got cleared", func() {
+		data.EventStream[0].Data = "$ top\r\n\x1b[H\x1b[2J"
+
+		err = editor.Repeat(data, 2, 4, 2)
+		Expect(err).To(Succeed())
+		Expect(events()[3]).To(Equal(
+			cast.Event{Time: 4, Type: "o", Data: "\x1bc\x1b[2J"}))
+	})
+
+	It("restores the size when the segment resizes the terminal", func() {
+		data.EventStream[2] = &cast.Event{Time: 3, Type: "r", Data: "100x30"}
+
+		err = editor.Repeat(data, 2, 4, 2)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 1, Type: "o", Data: "$ "},
+			{Time: 2, Type: "o", Data: "load: 1"},
+			{Time: 3, Type: "r", Data: "100x30"},
+			{Time: 4, Type: "r", Data: "80x24"},
+			{Time: 4, Type: "o", Data: "\x1bc$ "},
+			{Time: 4, Type: "o", Data: "load: 1"},
+			{Time: 5, Type: "r", Data: "100x30"},
+			{Time: 6, Type: "o", Data: "\n$ "},
+		}))
+	})
+})
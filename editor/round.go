@@ -0,0 +1,27 @@
+package editor
+
+import (
+	"math"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+)
+
+// TimePrecision is the number of decimal places that timestamps are
+// rounded to by the operations in this package (microseconds, just
+// like the asciinema recorder does).
+const TimePrecision = 6
+
+// RoundTime rounds a timestamp to `TimePrecision` decimal places,
+// getting rid of the floating point noise that arithmetic on
+// timestamps introduces (e.g., `1.2000000000000002`).
+func RoundTime(t float64) float64 {
+	scale := math.Pow(10, TimePrecision)
+	return math.Round(t*scale) / scale
+}
+
+// roundTimes rounds the timestamps of all events (see `RoundTime`).
+func roundTimes(events []*cast.Event) {
+	for _, ev := range events {
+		ev.Time = RoundTime(ev.Time)
+	}
+}
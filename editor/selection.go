@@ -0,0 +1,287 @@
+package editor
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Selection picks the events of a cast that an edit acts on.
+//
+// Events can be located by timestamps (`Times`), positions (`Indexes`),
+// marker sections (`Markers`) and data (`Match`). An event that is
+// located by any of them is selected; when none of them is specified,
+// the whole event stream is. `Types` then narrows the selection down to
+// the events of the given types.
+//
+// Delays are selected when both of the events around them are located
+// by the same selector and the event that follows the delay is
+// selected (see `Delays`).
+type Selection struct {
+	// Times locates the events whose timestamps lie within one of the
+	// ranges.
+	Times []TimeRange
+
+	// Indexes locates the events whose positions lie within one of the
+	// ranges.
+	Indexes []IndexRange
+
+	// Markers locates the sections that start at the marker events
+	// (`m`) with the given labels, going until the next marker (or the
+	// end of the cast).
+	Markers []string
+
+	// Match locates the events whose data matches the expression.
+	// Consecutive matching events form a single section.
+	Match *regexp.Regexp
+
+	// Before and After pad each of the sections located by `Match`,
+	// locating also the events that happen up to `Before` seconds
+	// before it and up to `After` seconds after it.
+	Before float64
+	After  float64
+
+	// Types restricts the selection to the events of the given types
+	// (e.g., `i` for the keystrokes). When empty, events of any type
+	// are selected.
+	Types []string
+}
+
+// Ranges retrieves the selected events of a cast, grouping consecutive
+// positions into ranges (sorted by position).
+//
+// Fails if no event is selected.
+func (s *Selection) Ranges(c *cast.Cast) (ranges []IndexRange, err error) {
+	var located []IndexRange
+
+	located, err = s.locate(c)
+	if err != nil {
+		return
+	}
+
+	ranges = make([]IndexRange, 0)
+
+	for _, r := range located {
+		for idx := r.From; idx <= r.To; idx++ {
+			if !s.hasType(c.EventStream[idx]) {
+				continue
+			}
+
+			last := len(ranges) - 1
+			if last >= 0 && ranges[last].To+1 >= idx {
+				ranges[last].To = idx
+				continue
+			}
+
+			ranges = append(ranges, IndexRange{From: idx, To: idx})
+		}
+	}
+
+	if len(ranges) == 0 {
+		err = errors.Errorf("selection doesn't match any event")
+		return
+	}
+
+	return
+}
+
+// Delays retrieves which delays of a cast are selected, where
+// `delays[idx]` refers to the delay that precedes the event at position
+// `idx` (thus, `delays[0]` is always false).
+//
+// Given that `Types` only applies to the event that follows a delay,
+// `Types: []string{"i"}` selects the pauses before each keystroke,
+// including the one after the output of a command.
+func (s *Selection) Delays(c *cast.Cast) (delays []bool, err error) {
+	var located []IndexRange
+
+	located, err = s.locate(c)
+	if err != nil {
+		return
+	}
+
+	delays = make([]bool, len(c.EventStream))
+
+	for _, r := range located {
+		for idx := r.From + 1; idx <= r.To; idx++ {
+			delays[idx] = s.hasType(c.EventStream[idx])
+		}
+	}
+
+	return
+}
+
+// locate resolves the location selectors into sorted ranges of
+// positions, merging the ones that overlap.
+//
+// Ranges that only touch each other are kept apart so that the delay
+// between them doesn't get selected.
+func (s *Selection) locate(c *cast.Cast) (ranges []IndexRange, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	var (
+		last    = len(c.EventStream) - 1
+		located = make([]IndexRange, 0)
+		r       IndexRange
+		found   bool
+	)
+
+	if len(s.Times) == 0 && len(s.Indexes) == 0 &&
+		len(s.Markers) == 0 && s.Match == nil {
+		ranges = []IndexRange{{From: 0, To: last}}
+		return
+	}
+
+	if s.Before < 0 || s.After < 0 {
+		err = errors.Errorf("padding must not be negative")
+		return
+	}
+
+	for _, tRange := range s.Times {
+		if tRange.From > tRange.To {
+			err = errors.Errorf("`from` cant be bigger than `to`")
+			return
+		}
+
+		r, found = timeRangeIndexes(c, tRange)
+		if found {
+			located = append(located, r)
+		}
+	}
+
+	for _, iRange := range s.Indexes {
+		err = iRange.validate(c)
+		if err != nil {
+			return
+		}
+
+		located = append(located, iRange)
+	}
+
+	for _, label := range s.Markers {
+		found = false
+
+		for idx, ev := range c.EventStream {
+			if ev.Type != "m" || ev.Data != label {
+				continue
+			}
+
+			r = IndexRange{From: idx, To: last}
+			for next := idx + 1; next <= last; next++ {
+				if c.EventStream[next].Type == "m" {
+					r.To = next - 1
+					break
+				}
+			}
+
+			found = true
+			located = append(located, r)
+		}
+
+		if !found {
+			err = errors.Errorf("couldn't find marker '%s'", label)
+			return
+		}
+	}
+
+	if s.Match != nil {
+		for _, run := range s.matches(c) {
+			r, found = timeRangeIndexes(c, TimeRange{
+				From: c.EventStream[run.From].Time - s.Before,
+				To:   c.EventStream[run.To].Time + s.After,
+			})
+			if found {
+				located = append(located, r)
+			}
+		}
+	}
+
+	sort.SliceStable(located, func(i, j int) bool {
+		return located[i].From < located[j].From
+	})
+
+	ranges = make([]IndexRange, 0, len(located))
+
+	for _, r = range located {
+		prev := len(ranges) - 1
+		if prev >= 0 && r.From < ranges[prev].To {
+			if r.To > ranges[prev].To {
+				ranges[prev].To = r.To
+			}
+			continue
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	if len(ranges) == 0 {
+		err = errors.Errorf("selection doesn't match any event")
+		return
+	}
+
+	return
+}
+
+// matches finds the events whose data matches `Match`, grouping
+// consecutive ones into ranges.
+func (s *Selection) matches(c *cast.Cast) (runs []IndexRange) {
+	for idx, ev := range c.EventStream {
+		if !s.Match.MatchString(ev.Data) {
+			continue
+		}
+
+		last := len(runs) - 1
+		if last >= 0 && runs[last].To+1 == idx {
+			runs[last].To = idx
+			continue
+		}
+
+		runs = append(runs, IndexRange{From: idx, To: idx})
+	}
+
+	return
+}
+
+// hasType verifies whether an event is of one of the selected types.
+func (s *Selection) hasType(ev *cast.Event) bool {
+	if len(s.Types) == 0 {
+		return true
+	}
+
+	for _, evType := range s.Types {
+		if ev.Type == evType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// timeRangeIndexes finds the positions of the first and last events
+// whose timestamps lie within a time range, if any.
+func timeRangeIndexes(c *cast.Cast, tRange TimeRange) (r IndexRange, found bool) {
+	for idx, ev := range c.EventStream {
+		if ev.Time < tRange.From || ev.Time > tRange.To {
+			continue
+		}
+
+		if !found {
+			r.From = idx
+			found = true
+		}
+
+		r.To = idx
+	}
+
+	return
+}
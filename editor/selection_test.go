@@ -0,0 +1,142 @@
+package editor_test
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Selection", func() {
+	var (
+		data      *cast.Cast
+		selection editor.Selection
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "m", Data: "intro"},
+				{Time: 1, Type: "i", Data: "npm install\r"},
+				{Time: 2, Type: "o", Data: "npm install\r\n"},
+				{Time: 3, Type: "o", Data: "added 120 packages"},
+				{Time: 4, Type: "m", Data: "build"},
+				{Time: 5, Type: "i", Data: "make\r"},
+				{Time: 6, Type: "o", Data: "done"},
+			},
+		}
+		selection = editor.Selection{}
+	})
+
+	Describe("Ranges", func() {
+		It("selects the whole event stream by default", func() {
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 0, To: 6}}))
+		})
+
+		It("selects events by time", func() {
+			selection.Times = []editor.TimeRange{{From: 0.5, To: 2.5}}
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 1, To: 2}}))
+		})
+
+		It("selects marker sections", func() {
+			selection.Markers = []string{"intro"}
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 0, To: 3}}))
+		})
+
+		It("selects the last marker section up to the end", func() {
+			selection.Markers = []string{"build"}
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 4, To: 6}}))
+		})
+
+		It("fails with an unknown marker", func() {
+			selection.Markers = []string{"nope"}
+
+			_, err := selection.Ranges(data)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("selects matching events with padding", func() {
+			selection.Match = regexp.MustCompile(`added \d+ packages`)
+			selection.Before = 1
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 2, To: 3}}))
+		})
+
+		It("joins the events located by different selectors", func() {
+			selection.Indexes = []editor.IndexRange{{From: 1, To: 1}}
+			selection.Times = []editor.TimeRange{{From: 2, To: 2}}
+			selection.Match = regexp.MustCompile(`done`)
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{
+				{From: 1, To: 2},
+				{From: 6, To: 6},
+			}))
+		})
+
+		It("narrows the selection down by type", func() {
+			selection.Markers = []string{"intro"}
+			selection.Types = []string{"o"}
+
+			ranges, err := selection.Ranges(data)
+			Expect(err).To(Succeed())
+			Expect(ranges).To(Equal([]editor.IndexRange{{From: 2, To: 3}}))
+		})
+
+		It("fails if nothing matches", func() {
+			selection.Match = regexp.MustCompile(`yarn`)
+
+			_, err := selection.Ranges(data)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with out of bounds indexes", func() {
+			selection.Indexes = []editor.IndexRange{{From: 5, To: 7}}
+
+			_, err := selection.Ranges(data)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	Describe("Delays", func() {
+		It("doesn't select the delay between separate ranges", func() {
+			selection.Indexes = []editor.IndexRange{
+				{From: 0, To: 2},
+				{From: 3, To: 4},
+			}
+
+			delays, err := selection.Delays(data)
+			Expect(err).To(Succeed())
+			Expect(delays).To(Equal([]bool{
+				false, true, true, false, true, false, false,
+			}))
+		})
+
+		It("selects the delays preceding events of the types", func() {
+			selection.Types = []string{"i"}
+
+			delays, err := selection.Delays(data)
+			Expect(err).To(Succeed())
+			Expect(delays).To(Equal([]bool{
+				false, true, false, false, false, true, false,
+			}))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"math"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Shift offsets the timestamps of the events that lie between `from`
+// and `to` (both included) by `by` seconds (which might be negative).
+//
+// The shift must neither make timestamps negative nor change the order
+// of the events: shifting a range of events past the events around it
+// fails, leaving the cast untouched.
+func Shift(c *cast.Cast, by, from, to float64) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if from > to {
+		err = errors.Errorf("`from` cant be bigger than `to`")
+		return
+	}
+
+	var (
+		times = make([]float64, len(c.EventStream))
+		prev  float64
+	)
+
+	for idx, ev := range c.EventStream {
+		times[idx] = ev.Time
+		if ev.Time >= from && ev.Time <= to {
+			times[idx] = RoundTime(ev.Time + by)
+		}
+
+		if times[idx] < prev {
+			if times[idx] < 0 {
+				err = errors.Errorf(
+					"shift would make event %d start before 0", idx)
+				return
+			}
+
+			err = errors.Errorf(
+				"shift would move event %d before the event that precedes it",
+				idx)
+			return
+		}
+
+		prev = times[idx]
+	}
+
+	for idx, ev := range c.EventStream {
+		ev.Time = times[idx]
+	}
+
+	return
+}
+
+// Rebase shifts all of the events of a cast so that the first one
+// happens at 0.
+func Rebase(c *cast.Cast) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	err = Shift(c, -c.EventStream[0].Time, 0, math.MaxFloat64)
+	return
+}
@@ -0,0 +1,92 @@
+package editor_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Shift", func() {
+	var (
+		data *cast.Cast
+		err  error
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+				{Time: 3, Type: "o", Data: "c"},
+				{Time: 5, Type: "o", Data: "d"},
+			},
+		}
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			err = editor.Shift(nil, 1, 0, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with from > to", func() {
+			err = editor.Shift(data, 1, 2, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails if events would start before 0", func() {
+			err = editor.Shift(data, -1.5, 0, math.MaxFloat64)
+			Expect(err).ToNot(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 3, 5}))
+		})
+
+		It("fails if events would change order", func() {
+			err = editor.Shift(data, 1.5, 2, 2)
+			Expect(err).ToNot(Succeed())
+			Expect(times(data)).To(Equal([]float64{1, 2, 3, 5}))
+		})
+	})
+
+	It("shifts all events", func() {
+		err = editor.Shift(data, 2.5, 0, math.MaxFloat64)
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{3.5, 4.5, 5.5, 7.5}))
+	})
+
+	It("shifts a range of events", func() {
+		err = editor.Shift(data, -0.9, 3, 5)
+		Expect(err).To(Succeed())
+		Expect(times(data)).To(Equal([]float64{1, 2, 2.1, 4.1}))
+	})
+})
+
+var _ = Describe("Rebase", func() {
+	It("makes the first event start at 0", func() {
+		data := &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1.3, Type: "o", Data: "a"},
+				{Time: 2, Type: "o", Data: "b"},
+			},
+		}
+
+		Expect(editor.Rebase(data)).To(Succeed())
+		Expect(data.EventStream[0].Time).To(Equal(0.0))
+		Expect(data.EventStream[1].Time).To(Equal(0.7))
+	})
+
+	It("fails with an empty event stream", func() {
+		Expect(editor.Rebase(&cast.Cast{})).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("RoundTime", func() {
+	It("rounds to microseconds", func() {
+		Expect(editor.RoundTime(1.2000000000000002)).To(Equal(1.2))
+		Expect(editor.RoundTime(4.0273319999999995)).To(Equal(4.027332))
+		Expect(editor.RoundTime(0.0000004)).To(Equal(0.0))
+	})
+})
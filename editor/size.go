@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// SizePolicy determines how differing terminal sizes get reconciled when
+// the events of a cast are combined with the events of another one.
+type SizePolicy string
+
+const (
+	// SizeFail makes the operation fail if the sizes differ.
+	SizeFail SizePolicy = "fail"
+
+	// SizeMax makes the resulting cast have the biggest width and
+	// height, letterboxing the smaller contents (they get rendered at
+	// the top-left corner of the bigger terminal).
+	SizeMax SizePolicy = "max"
+
+	// SizeResize makes the resulting cast carry resize events (`r`)
+	// whenever the size changes.
+	SizeResize SizePolicy = "resize"
+)
+
+// ParseSizePolicy converts a string into a SizePolicy, failing if it
+// doesn't correspond to a known policy.
+func ParseSizePolicy(input string) (policy SizePolicy, err error) {
+	policy = SizePolicy(input)
+
+	switch policy {
+	case SizeFail, SizeMax, SizeResize:
+	default:
+		err = errors.Errorf(
+			"unknown size policy '%s': must be `fail`, `max` or `resize`",
+			input)
+	}
+
+	return
+}
+
+// sizeAt computes the terminal size right before the event at position
+// `idx` of the event stream, taking into account the resize events that
+// precede it.
+func sizeAt(c *cast.Cast, idx int) (width, height uint) {
+	width, height = c.Header.Width, c.Header.Height
+
+	for _, ev := range c.EventStream[:idx] {
+		if ev.Type != "r" {
+			continue
+		}
+
+		w, h, err := cast.ParseSize(ev.Data)
+		if err != nil {
+			continue
+		}
+
+		width, height = w, h
+	}
+
+	return
+}
+
+// resizeEvent creates a resize event that changes the terminal size to
+// `width`x`height` at a given time.
+func resizeEvent(time float64, width, height uint) *cast.Event {
+	return &cast.Event{
+		Time: time,
+		Type: "r",
+		Data: cast.FormatSize(width, height),
+	}
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+
+	return b
+}
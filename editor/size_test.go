@@ -0,0 +1,28 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("ParseSizePolicy", func() {
+	It("fails with unknown policies", func() {
+		_, err := editor.ParseSizePolicy("foo")
+		Expect(err).ToNot(Succeed())
+
+		_, err = editor.ParseSizePolicy("")
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("parses known policies", func() {
+		for _, policy := range []editor.SizePolicy{
+			editor.SizeFail, editor.SizeMax, editor.SizeResize,
+		} {
+			parsed, err := editor.ParseSizePolicy(string(policy))
+			Expect(err).To(Succeed())
+			Expect(parsed).To(Equal(policy))
+		}
+	})
+})
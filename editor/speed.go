@@ -1,13 +1,58 @@
 package editor
 
 import (
+	"sort"
+
 	"github.com/cirocosta/asciinema-edit/cast"
 	"github.com/pkg/errors"
 )
 
+// SpeedRange describes a segment of the event stream (from the event at
+// `From` to the event at `To`) whose delays get multiplied by `Factor`.
+type SpeedRange struct {
+	// From indicates the timestamp of the initial event
+	From float64
+	// To indicates the timestamp of the final event
+	To float64
+	// Factor is the number by which delays are multiplied by
+	Factor float64
+}
+
+// FactorBounds delimits the factors accepted by `Speed` and
+// `SpeedRanges` (both bounds included).
+type FactorBounds struct {
+	Min float64
+	Max float64
+}
+
+// DefaultFactorBounds are the bounds used by `Speed`.
+var DefaultFactorBounds = FactorBounds{
+	Min: 0.1,
+	Max: 10,
+}
+
 // Speed updates the cast speed by multiplying all of the
 // timestamps in a given range by a given factor.
 func Speed(c *cast.Cast, factor, from, to float64) (err error) {
+	err = SpeedRanges(c, []SpeedRange{{
+		From:   from,
+		To:     to,
+		Factor: factor,
+	}}, DefaultFactorBounds)
+	return
+}
+
+// SpeedRanges updates the speed of multiple segments of the cast at
+// once.
+//
+// All ranges are resolved against the original timestamps (i.e., the
+// change in the timing of a segment doesn't affect which events the
+// following ranges refer to) and are applied atomically: if any of
+// them is invalid, the cast is left untouched.
+//
+// Ranges must not overlap, but might share their bounds (e.g., `1,2`
+// and `2,3`).
+func SpeedRanges(c *cast.Cast, ranges []SpeedRange, bounds FactorBounds) (err error) {
 	if c == nil {
 		err = errors.Errorf("cast must not be nil")
 		return
@@ -18,71 +63,191 @@ func Speed(c *cast.Cast, factor, from, to float64) (err error) {
 		return
 	}
 
-	if factor > 10 || factor < 0.1 {
-		err = errors.Errorf("factor must be within 0.1 and 10 range")
+	var resolved = make([]speedIndexRange, 0, len(ranges))
+
+	for _, sRange := range ranges {
+		if sRange.From >= sRange.To {
+			err = errors.Errorf("`from` must not be greater or equal than `to`")
+			return
+		}
+
+		var (
+			fromIdx = -1
+			toIdx   = -1
+		)
+
+		for idx, ev := range c.EventStream {
+			if ev.Time == sRange.From {
+				fromIdx = idx
+			}
+
+			if ev.Time == sRange.To {
+				toIdx = idx
+			}
+		}
+
+		if fromIdx == -1 {
+			err = errors.Errorf("couldn't find initial frame")
+			return
+		}
+
+		if toIdx == -1 {
+			err = errors.Errorf("couldn't find final frame")
+			return
+		}
+
+		resolved = append(resolved, speedIndexRange{
+			From:   fromIdx,
+			To:     toIdx,
+			Factor: sRange.Factor,
+		})
+	}
+
+	err = speedIndexRanges(c, resolved, bounds)
+	return
+}
+
+// speedIndexRange describes a segment of the event stream (from the
+// event at position `From` to the event at position `To`) whose delays
+// get multiplied by `Factor`.
+type speedIndexRange struct {
+	From   int
+	To     int
+	Factor float64
+}
+
+// speedIndexRanges performs the same as `SpeedRanges`, but with ranges
+// delimited by the position of the events.
+func speedIndexRanges(c *cast.Cast, ranges []speedIndexRange, bounds FactorBounds) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
 		return
 	}
 
-	if from >= to {
-		err = errors.Errorf("`from` must not be greater or equal than `to`")
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must be nonempty")
 		return
 	}
 
-	var (
-		fromIdx = -1
-		toIdx   = -1
-	)
+	if len(ranges) == 0 {
+		err = errors.Errorf("at least one range must be specified")
+		return
+	}
 
-	for idx, ev := range c.EventStream {
-		if ev.Time == from {
-			fromIdx = idx
+	if bounds.Min <= 0 || bounds.Min > bounds.Max {
+		err = errors.Errorf("factor bounds must satisfy 0 < min <= max")
+		return
+	}
+
+	var resolved = make([]speedIndexRange, len(ranges))
+
+	copy(resolved, ranges)
+
+	for _, sRange := range resolved {
+		if sRange.Factor > bounds.Max || sRange.Factor < bounds.Min {
+			err = errors.Errorf("factor must be within %g and %g range",
+				bounds.Min, bounds.Max)
+			return
+		}
+
+		err = IndexRange{From: sRange.From, To: sRange.To}.validate(c)
+		if err != nil {
+			return
+		}
+
+		if sRange.From == sRange.To {
+			err = errors.Errorf("`from` must not be greater or equal than `to`")
+			return
 		}
+	}
+
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].From < resolved[j].From
+	})
 
-		if ev.Time == to {
-			toIdx = idx
+	for i := 1; i < len(resolved); i++ {
+		if resolved[i].From < resolved[i-1].To {
+			err = errors.Errorf("speed ranges must not overlap")
+			return
 		}
 	}
 
-	if fromIdx == -1 {
-		err = errors.Errorf("couldn't find initial frame")
+	var factors = make([]float64, len(c.EventStream))
+
+	for i := range factors {
+		factors[i] = 1
+	}
+
+	for _, iRange := range resolved {
+		for i := iRange.From + 1; i <= iRange.To; i++ {
+			factors[i] = iRange.Factor
+		}
+	}
+
+	applyFactors(c, factors)
+	return
+}
+
+// SpeedSelection updates the speed of the selected delays of a cast
+// (see `Selection.Delays`), multiplying them by `factor`.
+func SpeedSelection(c *cast.Cast, factor float64, selection Selection, bounds FactorBounds) (err error) {
+	if bounds.Min <= 0 || bounds.Min > bounds.Max {
+		err = errors.Errorf("factor bounds must satisfy 0 < min <= max")
+		return
+	}
+
+	if factor > bounds.Max || factor < bounds.Min {
+		err = errors.Errorf("factor must be within %g and %g range",
+			bounds.Min, bounds.Max)
 		return
 	}
 
-	if toIdx == -1 {
-		err = errors.Errorf("couldn't find final frame")
+	var delays []bool
+
+	delays, err = selection.Delays(c)
+	if err != nil {
 		return
 	}
 
 	var (
-		i                int
-		k                int
-		delta            float64
-		newDelta         float64
-		accumulatedDelta float64
-		deltas           = make([]float64, toIdx-fromIdx)
+		factors  = make([]float64, len(c.EventStream))
+		selected bool
 	)
 
-	k = 0
-	for i = fromIdx; i < toIdx; i++ {
-		delta = c.EventStream[i+1].Time - c.EventStream[i].Time
-		newDelta = delta * factor
-		accumulatedDelta += (newDelta - delta)
+	for i := range factors {
+		factors[i] = 1
 
-		deltas[k] = newDelta
-		k++
+		if delays[i] {
+			factors[i] = factor
+			selected = true
+		}
 	}
 
-	k = 0
-	for i = fromIdx; i < toIdx; i++ {
-		c.EventStream[i+1].Time = c.EventStream[i].Time + deltas[k]
-		k++
+	if !selected {
+		err = errors.Errorf("selection doesn't cover any delay")
+		return
 	}
 
-	if toIdx+1 < len(c.EventStream) {
-		for _, remainingElem := range c.EventStream[toIdx+1:] {
-			remainingElem.Time += accumulatedDelta
-		}
+	applyFactors(c, factors)
+	return
+}
+
+// applyFactors multiplies the delay that precedes each event by the
+// factor at the same position.
+func applyFactors(c *cast.Cast, factors []float64) {
+	var (
+		times = make([]float64, len(c.EventStream))
+		shift float64
+	)
+
+	for i, ev := range c.EventStream {
+		times[i] = ev.Time
 	}
 
-	return
+	for i := 1; i < len(c.EventStream); i++ {
+		shift += (times[i] - times[i-1]) * (factors[i] - 1)
+		c.EventStream[i].Time = times[i] + shift
+	}
+
+	roundTimes(c.EventStream)
 }
@@ -1,6 +1,8 @@
 package editor_test
 
 import (
+	"regexp"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -180,4 +182,155 @@ var _ = Describe("Speed", func() {
 			})
 		})
 	})
+	Describe("SpeedRanges", func() {
+		var (
+			data                                   *cast.Cast
+			event1, event2, event3, event4, event5 *cast.Event
+			bounds                                 = editor.DefaultFactorBounds
+		)
+
+		BeforeEach(func() {
+			event1 = &cast.Event{Time: 1}
+			event2 = &cast.Event{Time: 2}
+			event3 = &cast.Event{Time: 3}
+			event4 = &cast.Event{Time: 4}
+			event5 = &cast.Event{Time: 5}
+
+			data = &cast.Cast{
+				EventStream: []*cast.Event{
+					event1, event2, event3, event4, event5,
+				},
+			}
+		})
+
+		Context("parameter validation", func() {
+			It("fails without ranges", func() {
+				err := editor.SpeedRanges(data, nil, bounds)
+				Expect(err).ToNot(Succeed())
+			})
+
+			It("fails with invalid bounds", func() {
+				err := editor.SpeedRanges(data, []editor.SpeedRange{
+					{From: 1, To: 2, Factor: 1},
+				}, editor.FactorBounds{Min: 2, Max: 1})
+				Expect(err).ToNot(Succeed())
+
+				err = editor.SpeedRanges(data, []editor.SpeedRange{
+					{From: 1, To: 2, Factor: 1},
+				}, editor.FactorBounds{Min: 0, Max: 1})
+				Expect(err).ToNot(Succeed())
+			})
+
+			It("fails with overlapping ranges", func() {
+				err := editor.SpeedRanges(data, []editor.SpeedRange{
+					{From: 1, To: 3, Factor: 2},
+					{From: 2, To: 4, Factor: 2},
+				}, bounds)
+				Expect(err).ToNot(Succeed())
+			})
+
+			It("leaves the cast untouched if any range is invalid", func() {
+				err := editor.SpeedRanges(data, []editor.SpeedRange{
+					{From: 1, To: 2, Factor: 2},
+					{From: 3, To: 4.5, Factor: 2},
+				}, bounds)
+				Expect(err).ToNot(Succeed())
+				Expect(event2.Time).To(Equal(float64(2)))
+			})
+		})
+
+		It("resolves all ranges against the original timestamps", func() {
+			err := editor.SpeedRanges(data, []editor.SpeedRange{
+				{From: 3, To: 5, Factor: 0.5},
+				{From: 1, To: 2, Factor: 2},
+				{From: 2, To: 3, Factor: 3},
+			}, bounds)
+			Expect(err).To(Succeed())
+
+			Expect(event1.Time).To(Equal(float64(1)))
+			Expect(event2.Time).To(Equal(float64(3)))
+			Expect(event3.Time).To(Equal(float64(6)))
+			Expect(event4.Time).To(Equal(float64(6.5)))
+			Expect(event5.Time).To(Equal(float64(7)))
+		})
+
+		It("accepts factors within custom bounds", func() {
+			err := editor.SpeedRanges(data, []editor.SpeedRange{
+				{From: 1, To: 2, Factor: 20},
+			}, editor.FactorBounds{Min: 0.01, Max: 100})
+			Expect(err).To(Succeed())
+			Expect(event2.Time).To(Equal(float64(21)))
+		})
+	})
+})
+
+var _ = Describe("Speed precision", func() {
+	It("rounds timestamps to microseconds", func() {
+		data := &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 1.3, Type: "o", Data: "b"},
+				{Time: 1.7, Type: "o", Data: "c"},
+			},
+		}
+
+		Expect(editor.Speed(data, 0.1, 1, 1.7)).To(Succeed())
+		Expect(data.EventStream[1].Time).To(Equal(1.03))
+		Expect(data.EventStream[2].Time).To(Equal(1.07))
+	})
+})
+
+var _ = Describe("SpeedSelection", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "i", Data: "npm install\r"},
+				{Time: 1, Type: "o", Data: "fetching"},
+				{Time: 5, Type: "o", Data: "added 3 packages"},
+				{Time: 6, Type: "o", Data: "$ "},
+			},
+		}
+	})
+
+	It("updates only the selected delays", func() {
+		err := editor.SpeedSelection(data, 0.5, editor.Selection{
+			Match:  regexp.MustCompile(`added`),
+			Before: 4,
+		}, editor.DefaultFactorBounds)
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[1].Time).To(Equal(1.0))
+		Expect(data.EventStream[2].Time).To(Equal(3.0))
+		Expect(data.EventStream[3].Time).To(Equal(4.0))
+	})
+
+	It("updates the delays between the events located by position", func() {
+		err := editor.SpeedSelection(data, 0.5, editor.Selection{
+			Indexes: []editor.IndexRange{{From: 2, To: 3}},
+		}, editor.DefaultFactorBounds)
+		Expect(err).To(Succeed())
+		Expect(data.EventStream[2].Time).To(Equal(5.0))
+		Expect(data.EventStream[3].Time).To(Equal(5.5))
+	})
+
+	It("fails with out of bounds indexes", func() {
+		err := editor.SpeedSelection(data, 0.5, editor.Selection{
+			Indexes: []editor.IndexRange{{From: 0, To: 4}},
+		}, editor.DefaultFactorBounds)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails if no delay is selected", func() {
+		err := editor.SpeedSelection(data, 0.5, editor.Selection{
+			Indexes: []editor.IndexRange{{From: 1, To: 1}},
+		}, editor.DefaultFactorBounds)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("fails with a factor out of bounds", func() {
+		err := editor.SpeedSelection(data, 20, editor.Selection{},
+			editor.DefaultFactorBounds)
+		Expect(err).ToNot(Succeed())
+	})
 })
@@ -0,0 +1,234 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Split cuts a cast into multiple casts at the times specified by `at`.
+//
+// Each part contains the events that happen from a cut point (included)
+// up to the next one (excluded), having their timestamps re-based so
+// that the part starts at 0.
+//
+// Given that the terminal state at a cut point depends on the output
+// that came before it, every part but the first gets prefixed with an
+// output event (at 0) that replays such output, as well as a header
+// with the terminal size at that point. Only the output since the last
+// time that the screen got entirely cleared is replayed (see
+// `outputBefore`).
+//
+// Cut points that would produce empty parts are ignored.
+//
+// It assumes that the provided `cast` is entirely valid (see
+// `github.com/cirocosta/asciinema-edit/cast#Validate`).
+func Split(c *cast.Cast, at []float64) (parts []*cast.Cast, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if len(at) == 0 {
+		err = errors.Errorf("at least one cut point must be specified")
+		return
+	}
+
+	var (
+		points = make([]float64, len(at))
+		start  = 0
+		from   float64
+	)
+
+	copy(points, at)
+	sort.Float64s(points)
+
+	parts = make([]*cast.Cast, 0, len(points)+1)
+
+	for _, point := range points {
+		if point < 0 {
+			err = errors.Errorf("cut points must not be negative")
+			parts = nil
+			return
+		}
+
+		end := start
+		for end < len(c.EventStream) && c.EventStream[end].Time < point {
+			end++
+		}
+
+		if end == start {
+			continue
+		}
+
+		parts = append(parts, part(c, start, end, from))
+		start, from = end, point
+	}
+
+	if start < len(c.EventStream) {
+		parts = append(parts, part(c, start, len(c.EventStream), from))
+	}
+
+	return
+}
+
+// MarkerTimes retrieves the timestamps of all the marker events (`m`).
+func MarkerTimes(c *cast.Cast) (times []float64) {
+	times = make([]float64, 0)
+
+	for _, ev := range c.EventStream {
+		if ev.Type == "m" {
+			times = append(times, ev.Time)
+		}
+	}
+
+	return
+}
+
+// IntervalTimes retrieves the timestamps that divide a cast in intervals
+// of `interval` seconds (e.g., `5 10 15` for a 17s cast and a 5s
+// interval).
+func IntervalTimes(c *cast.Cast, interval float64) (times []float64, err error) {
+	if interval <= 0 {
+		err = errors.Errorf("interval must be positive")
+		return
+	}
+
+	times = make([]float64, 0)
+	if len(c.EventStream) == 0 {
+		return
+	}
+
+	duration := c.EventStream[len(c.EventStream)-1].Time
+	for t := interval; t < duration; t += interval {
+		times = append(times, t)
+	}
+
+	return
+}
+
+// part creates a cast out of the events in `[start, end)`, re-basing
+// them to start at `from`.
+func part(c *cast.Cast, start, end int, from float64) (res *cast.Cast) {
+	res = &cast.Cast{
+		Header:      c.Header,
+		EventStream: make([]*cast.Event, 0, end-start+1),
+	}
+
+	res.Header.Width, res.Header.Height = sizeAt(c, start)
+
+	if state := outputBefore(c, start); state != "" {
+		res.EventStream = append(res.EventStream, &cast.Event{
+			Time: 0,
+			Type: "o",
+			Data: state,
+		})
+	}
+
+	for _, ev := range c.EventStream[start:end] {
+		res.EventStream = append(res.EventStream, &cast.Event{
+			Time: ev.Time - from,
+			Type: ev.Type,
+			Data: ev.Data,
+		})
+	}
+
+	roundTimes(res.EventStream)
+	return
+}
+
+// outputBefore concatenates the data of the output events that precede
+// the event at position `idx`, which, once replayed, brings a terminal
+// to the state it had right before such event.
+//
+// The output that precedes the last full clear of the screen (see
+// `lastClear`) is left out given that none of it remains visible. Modes
+// set before the clear (e.g., colors or the scrolling region) are thus
+// not restored.
+func outputBefore(c *cast.Cast, idx int) string {
+	var (
+		builder         strings.Builder
+		cleared, offset = lastClear(c, idx)
+	)
+
+	for pos, ev := range c.EventStream[cleared:idx] {
+		if ev.Type != "o" {
+			continue
+		}
+
+		if pos == 0 {
+			builder.WriteString(ev.Data[offset:])
+			continue
+		}
+
+		builder.WriteString(ev.Data)
+	}
+
+	return builder.String()
+}
+
+// lastClear finds the last full clear of the main screen (`ESC c` or
+// `ESC [ 2 J`) among the output events that precede the event at
+// position `idx`, indicating the position of the event and where the
+// sequence starts in its data.
+//
+// Clears of the alternate screen are not taken into account, given that
+// the main screen is displayed again once the program that switched to
+// it exits.
+//
+// If the screen never gets cleared, the beginning of the cast is
+// retrieved.
+func lastClear(c *cast.Cast, idx int) (cleared, offset int) {
+	var (
+		alternate bool
+		pending   string
+	)
+
+	for pos, ev := range c.EventStream[:idx] {
+		if ev.Type != "o" {
+			continue
+		}
+
+		var (
+			data  = pending + ev.Data
+			start = -len(pending)
+		)
+
+		pending = ""
+
+		for _, token := range ansi.Tokenize(data) {
+			if token.Incomplete {
+				pending = token.Data
+				break
+			}
+
+			switch token.Data {
+			case "\x1bc":
+				alternate = false
+				fallthrough
+			case "\x1b[2J":
+				// sequences that started in a previous event are
+				// skipped so that they don't get split apart.
+				if !alternate && start >= 0 {
+					cleared, offset = pos, start
+				}
+			case "\x1b[?47h", "\x1b[?1047h", "\x1b[?1049h":
+				alternate = true
+			case "\x1b[?47l", "\x1b[?1047l", "\x1b[?1049l":
+				alternate = false
+			}
+
+			start += len(token.Data)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,172 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Split", func() {
+	var data *cast.Cast
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			Header: cast.Header{Version: 2, Width: 80, Height: 24},
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "i", Data: "b"},
+				{Time: 3, Type: "m", Data: "chapter"},
+				{Time: 3, Type: "o", Data: "c"},
+				{Time: 4, Type: "r", Data: "100x30"},
+				{Time: 5, Type: "o", Data: "d"},
+			},
+		}
+	})
+
+	Describe("parameter validation", func() {
+		It("fails with nil cast", func() {
+			_, err := editor.Split(nil, []float64{1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with empty event stream", func() {
+			_, err := editor.Split(&cast.Cast{}, []float64{1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails without cut points", func() {
+			_, err := editor.Split(data, nil)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with negative cut points", func() {
+			_, err := editor.Split(data, []float64{-1})
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	Context("with cut points", func() {
+		var (
+			parts []*cast.Cast
+			err   error
+		)
+
+		JustBeforeEach(func() {
+			parts, err = editor.Split(data, []float64{4.5, 2.5})
+			Expect(err).To(Succeed())
+		})
+
+		It("creates a part per segment", func() {
+			Expect(parts).To(HaveLen(3))
+		})
+
+		It("keeps the first part as is", func() {
+			Expect(parts[0].Header.Width).To(Equal(uint(80)))
+			Expect(parts[0].EventStream).To(Equal([]*cast.Event{
+				{Time: 1, Type: "o", Data: "a"},
+				{Time: 2, Type: "i", Data: "b"},
+			}))
+		})
+
+		It("re-bases the other parts, prefixing them with the output so far", func() {
+			Expect(parts[1].EventStream).To(Equal([]*cast.Event{
+				{Time: 0, Type: "o", Data: "a"},
+				{Time: 0.5, Type: "m", Data: "chapter"},
+				{Time: 0.5, Type: "o", Data: "c"},
+				{Time: 1.5, Type: "r", Data: "100x30"},
+			}))
+
+			Expect(parts[2].EventStream).To(Equal([]*cast.Event{
+				{Time: 0, Type: "o", Data: "ac"},
+				{Time: 0.5, Type: "o", Data: "d"},
+			}))
+		})
+
+		It("carries the terminal size at the cut point", func() {
+			Expect(parts[1].Header.Width).To(Equal(uint(80)))
+			Expect(parts[2].Header.Width).To(Equal(uint(100)))
+			Expect(parts[2].Header.Height).To(Equal(uint(30)))
+		})
+
+		It("produces valid casts", func() {
+			for _, part := range parts {
+				_, err = cast.Validate(part)
+				Expect(err).To(Succeed())
+			}
+		})
+	})
+
+	Describe("replaying the output before a cut point", func() {
+		BeforeEach(func() {
+			data.EventStream = []*cast.Event{
+				{Time: 1, Type: "o", Data: "$ ls\r\n"},
+				{Time: 2, Type: "o", Data: "a b\r\n$ "},
+				{Time: 3, Type: "o", Data: "clear\r\n\x1b[H\x1b[2J"},
+				{Time: 4, Type: "o", Data: "$ "},
+				{Time: 5, Type: "o", Data: "vim\r\n"},
+				{Time: 6, Type: "o", Data: "\x1b[?1049h\x1b[2J~"},
+				{Time: 7, Type: "o", Data: "\x1b[?1049l$ "},
+			}
+		})
+
+		It("starts at the last time the screen got cleared", func() {
+			parts, err := editor.Split(data, []float64{5})
+			Expect(err).To(Succeed())
+			Expect(parts[1].EventStream[0]).To(Equal(&cast.Event{
+				Time: 0, Type: "o", Data: "\x1b[2J$ ",
+			}))
+		})
+
+		It("starts at a reset of the terminal", func() {
+			data.EventStream[3].Data = "\x1bc$ "
+
+			parts, err := editor.Split(data, []float64{5})
+			Expect(err).To(Succeed())
+			Expect(parts[1].EventStream[0].Data).To(Equal("\x1bc$ "))
+		})
+
+		It("doesn't take clears of the alternate screen into account", func() {
+			parts, err := editor.Split(data, []float64{7})
+			Expect(err).To(Succeed())
+			Expect(parts[1].EventStream[0].Data).To(Equal(
+				"\x1b[2J$ vim\r\n\x1b[?1049h\x1b[2J~"))
+		})
+
+		It("doesn't split sequences that span multiple events", func() {
+			data.EventStream[2].Data = "clear\r\n\x1b[H\x1b["
+			data.EventStream[3].Data = "2J$ "
+
+			parts, err := editor.Split(data, []float64{5})
+			Expect(err).To(Succeed())
+			Expect(parts[1].EventStream[0].Data).To(Equal(
+				"$ ls\r\na b\r\n$ clear\r\n\x1b[H\x1b[2J$ "))
+		})
+	})
+
+	It("ignores cut points that would produce empty parts", func() {
+		parts, err := editor.Split(data, []float64{0.5, 2.5, 2.7, 10})
+		Expect(err).To(Succeed())
+		Expect(parts).To(HaveLen(2))
+	})
+
+	Describe("MarkerTimes", func() {
+		It("retrieves the time of the markers", func() {
+			Expect(editor.MarkerTimes(data)).To(Equal([]float64{3}))
+		})
+	})
+
+	Describe("IntervalTimes", func() {
+		It("fails with non-positive interval", func() {
+			_, err := editor.IntervalTimes(data, 0)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("divides the cast in intervals", func() {
+			times, err := editor.IntervalTimes(data, 2)
+			Expect(err).To(Succeed())
+			Expect(times).To(Equal([]float64{2, 4}))
+		})
+	})
+})
@@ -0,0 +1,174 @@
+package editor
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// ChunkMode determines how `Spread` breaks the output into chunks.
+type ChunkMode string
+
+const (
+	// ChunkLines breaks the output after each line feed (scrolling
+	// effect).
+	ChunkLines ChunkMode = "lines"
+	// ChunkChars breaks the output after each character (typing
+	// effect).
+	ChunkChars ChunkMode = "chars"
+)
+
+// ParseChunkMode converts a string into a ChunkMode.
+func ParseChunkMode(input string) (mode ChunkMode, err error) {
+	mode = ChunkMode(input)
+
+	switch mode {
+	case ChunkLines, ChunkChars:
+	default:
+		err = errors.Errorf(
+			"unknown chunk mode '%s' (must be lines or chars)", input)
+	}
+
+	return
+}
+
+// SpreadOptions configures how `Spread` breaks large output events.
+type SpreadOptions struct {
+	// Mode determines the size of the chunks.
+	Mode ChunkMode
+
+	// Threshold is the minimum number of chunks that an event must
+	// be broken into for it to be spread.
+	Threshold int
+
+	// Duration is the time (in seconds) over which the chunks of an
+	// event are spread.
+	Duration float64
+}
+
+// Spread breaks large output (`o`) events into chunks (lines or
+// characters) that get evenly spread over `options.Duration` seconds,
+// delaying the events that follow by the same amount.
+//
+// Chunks never break escape sequences nor multi-byte characters apart:
+// escape sequences and control characters (other than line feeds) are
+// kept together with the chunk that follows them.
+//
+// Returns the number of events that got spread.
+func Spread(c *cast.Cast, options SpreadOptions) (spread int, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if options.Threshold < 2 {
+		err = errors.Errorf("threshold must be at least 2 chunks")
+		return
+	}
+
+	if options.Duration <= 0 {
+		err = errors.Errorf("duration must be positive")
+		return
+	}
+
+	switch options.Mode {
+	case ChunkLines, ChunkChars:
+	default:
+		err = errors.Errorf("unknown chunk mode '%s'", options.Mode)
+		return
+	}
+
+	var (
+		res   = make([]*cast.Event, 0, len(c.EventStream))
+		shift float64
+	)
+
+	for _, ev := range c.EventStream {
+		ev.Time += shift
+
+		if ev.Type != "o" {
+			res = append(res, ev)
+			continue
+		}
+
+		chunks := chunk(ev.Data, options.Mode)
+		if len(chunks) < options.Threshold {
+			res = append(res, ev)
+			continue
+		}
+
+		step := options.Duration / float64(len(chunks)-1)
+		for idx, data := range chunks {
+			res = append(res, &cast.Event{
+				Time: ev.Time + float64(idx)*step,
+				Type: ev.Type,
+				Data: data,
+			})
+		}
+
+		shift += options.Duration
+		spread++
+	}
+
+	c.EventStream = res
+	roundTimes(c.EventStream)
+	return
+}
+
+// chunk breaks terminal output into chunks according to a mode.
+func chunk(data string, mode ChunkMode) (chunks []string) {
+	var builder strings.Builder
+
+	chunks = make([]string, 0)
+
+	cut := func() {
+		chunks = append(chunks, builder.String())
+		builder.Reset()
+	}
+
+	for _, token := range ansi.Tokenize(data) {
+		switch {
+		case token.Kind == ansi.Text && mode == ChunkChars:
+			for len(token.Data) > 0 {
+				_, size := utf8.DecodeRuneInString(token.Data)
+				builder.WriteString(token.Data[:size])
+				token.Data = token.Data[size:]
+				cut()
+			}
+		case token.Kind == ansi.Control && token.Data == "\n":
+			builder.WriteString(token.Data)
+			cut()
+		default:
+			builder.WriteString(token.Data)
+		}
+	}
+
+	if builder.Len() == 0 {
+		return
+	}
+
+	// trailing escape sequences and control characters (e.g., a
+	// color reset) are kept with the last chunk.
+	if len(chunks) > 0 && !hasText(builder.String()) {
+		chunks[len(chunks)-1] += builder.String()
+		return
+	}
+
+	cut()
+
+	return
+}
+
+// hasText verifies whether terminal output contains any text.
+func hasText(data string) bool {
+	for _, token := range ansi.Tokenize(data) {
+		if token.Kind == ansi.Text {
+			return true
+		}
+	}
+
+	return false
+}
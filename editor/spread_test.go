@@ -0,0 +1,117 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Spread", func() {
+	var (
+		data    *cast.Cast
+		options editor.SpreadOptions
+		spread  int
+		err     error
+	)
+
+	events := func() (res []cast.Event) {
+		for _, ev := range data.EventStream {
+			res = append(res, *ev)
+		}
+		return
+	}
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 1, Type: "o", Data: "$ "},
+				{Time: 2, Type: "o", Data: "a\r\n\x1b[1mb\x1b[0m\r\nc\r\n"},
+				{Time: 3, Type: "o", Data: "$ "},
+			},
+		}
+
+		options = editor.SpreadOptions{
+			Mode:      editor.ChunkLines,
+			Threshold: 2,
+			Duration:  1,
+		}
+	})
+
+	Context("with invalid input", func() {
+		It("fails with nil cast", func() {
+			_, err = editor.Spread(nil, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with a threshold smaller than 2", func() {
+			options.Threshold = 1
+			_, err = editor.Spread(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with non-positive duration", func() {
+			options.Duration = 0
+			_, err = editor.Spread(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with unknown mode", func() {
+			options.Mode = "words"
+			_, err = editor.Spread(data, options)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	It("spreads lines over the duration", func() {
+		spread, err = editor.Spread(data, options)
+		Expect(err).To(Succeed())
+		Expect(spread).To(Equal(1))
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 1, Type: "o", Data: "$ "},
+			{Time: 2, Type: "o", Data: "a\r\n"},
+			{Time: 2.5, Type: "o", Data: "\x1b[1mb\x1b[0m\r\n"},
+			{Time: 3, Type: "o", Data: "c\r\n"},
+			{Time: 4, Type: "o", Data: "$ "},
+		}))
+	})
+
+	It("keeps events below the threshold", func() {
+		options.Threshold = 4
+		spread, err = editor.Spread(data, options)
+		Expect(err).To(Succeed())
+		Expect(spread).To(BeZero())
+		Expect(data.EventStream).To(HaveLen(3))
+	})
+
+	It("spreads characters without breaking sequences", func() {
+		data.EventStream = []*cast.Event{
+			{Time: 0, Type: "o", Data: "\x1b[31mé€\x1b[0m"},
+			{Time: 1, Type: "i", Data: "q"},
+		}
+		options.Mode = editor.ChunkChars
+		options.Duration = 2
+
+		spread, err = editor.Spread(data, options)
+		Expect(err).To(Succeed())
+		Expect(events()).To(Equal([]cast.Event{
+			{Time: 0, Type: "o", Data: "\x1b[31mé"},
+			{Time: 2, Type: "o", Data: "€\x1b[0m"},
+			{Time: 3, Type: "i", Data: "q"},
+		}))
+	})
+})
+
+var _ = Describe("ParseChunkMode", func() {
+	It("accepts known modes", func() {
+		mode, err := editor.ParseChunkMode("chars")
+		Expect(err).To(Succeed())
+		Expect(mode).To(Equal(editor.ChunkChars))
+	})
+
+	It("fails with unknown modes", func() {
+		_, err := editor.ParseChunkMode("words")
+		Expect(err).ToNot(Succeed())
+	})
+})
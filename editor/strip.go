@@ -0,0 +1,53 @@
+package editor
+
+import (
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// Strip removes all the events of the given types that happen from
+// `from` to `to` (both included).
+//
+// Differently from `Cut`, the timestamps of the remaining events are
+// not modified, keeping the timing of the cast intact.
+//
+// For instance, to remove the keystrokes captured by
+// `asciinema rec --stdin`:
+//
+//	Strip(c, []string{"i"}, 0, math.MaxFloat64)
+func Strip(c *cast.Cast, types []string, from, to float64) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(types) == 0 {
+		err = errors.Errorf("at least one event type must be specified")
+		return
+	}
+
+	if from > to {
+		err = errors.Errorf("`from` cant be bigger than `to`")
+		return
+	}
+
+	var (
+		stripped = make(map[string]bool, len(types))
+		kept     = make([]*cast.Event, 0, len(c.EventStream))
+	)
+
+	for _, evType := range types {
+		stripped[evType] = true
+	}
+
+	for _, ev := range c.EventStream {
+		if stripped[ev.Type] && ev.Time >= from && ev.Time <= to {
+			continue
+		}
+
+		kept = append(kept, ev)
+	}
+
+	c.EventStream = kept
+	return
+}
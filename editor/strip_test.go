@@ -0,0 +1,69 @@
+package editor_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Strip", func() {
+	var (
+		data                           *cast.Cast
+		event1, event2, event3, event4 *cast.Event
+	)
+
+	BeforeEach(func() {
+		event1 = &cast.Event{Time: 1, Type: "o", Data: "$ "}
+		event2 = &cast.Event{Time: 2, Type: "i", Data: "s"}
+		event3 = &cast.Event{Time: 3, Type: "m", Data: "marker"}
+		event4 = &cast.Event{Time: 4, Type: "i", Data: "3"}
+
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				event1, event2, event3, event4,
+			},
+		}
+	})
+
+	Describe("parameter validation", func() {
+		It("fails with nil cast", func() {
+			err := editor.Strip(nil, []string{"i"}, 0, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails without types", func() {
+			err := editor.Strip(data, nil, 0, 1)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with `from` > `to`", func() {
+			err := editor.Strip(data, []string{"i"}, 2, 1)
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	It("removes events of the given types", func() {
+		err := editor.Strip(data, []string{"i", "m"}, 0, math.MaxFloat64)
+		Expect(err).To(Succeed())
+		Expect(data.EventStream).To(Equal([]*cast.Event{event1}))
+	})
+
+	It("removes events only within the range", func() {
+		err := editor.Strip(data, []string{"i"}, 1, 3)
+		Expect(err).To(Succeed())
+		Expect(data.EventStream).To(Equal([]*cast.Event{
+			event1, event3, event4,
+		}))
+	})
+
+	It("keeps the timing of the remaining events", func() {
+		err := editor.Strip(data, []string{"i"}, 0, math.MaxFloat64)
+		Expect(err).To(Succeed())
+		Expect(event1.Time).To(Equal(float64(1)))
+		Expect(event3.Time).To(Equal(float64(3)))
+	})
+})
@@ -0,0 +1,206 @@
+package editor
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// TrimOptions configures how `Trim` removes idle time.
+type TrimOptions struct {
+	// Threshold is the amount of idle time (in seconds) kept before
+	// the first visible output and after the last one.
+	Threshold float64
+
+	// RemoveExit makes the final `exit` command (and everything
+	// after it) be removed before trimming.
+	RemoveExit bool
+}
+
+// Trim removes the idle time at the beginning and at the end of a cast.
+//
+// Leading idle time is the time before the first visible output (i.e.,
+// an output event that prints something other than whitespace and escape
+// sequences), while trailing idle time is the time after the last one.
+//
+// The events that happen before the first visible output (e.g., the
+// terminal title being set) are kept, having their timestamps re-based
+// so that the cast starts at 0.
+//
+// The heuristic is:
+//
+// 1. (optionally) find the final `exit` command in the output and
+//    remove it and all the events after it; then
+// 2. bring the events after the last visible output closer to it; then
+// 3. shift all the events so that the first visible output happens at
+//    `Threshold`.
+func Trim(c *cast.Cast, options TrimOptions) (err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if len(c.EventStream) == 0 {
+		err = errors.Errorf("event stream must not be empty")
+		return
+	}
+
+	if options.Threshold < 0 {
+		err = errors.Errorf("threshold must not be negative")
+		return
+	}
+
+	if options.RemoveExit {
+		if idx := finalExitIndex(c); idx != -1 {
+			c.EventStream = c.EventStream[:idx]
+		}
+	}
+
+	var (
+		first = -1
+		last  = -1
+	)
+
+	for idx, ev := range c.EventStream {
+		if !isVisible(ev) {
+			continue
+		}
+
+		if first == -1 {
+			first = idx
+		}
+
+		last = idx
+	}
+
+	if first == -1 {
+		err = errors.Errorf("cast has no visible output")
+		return
+	}
+
+	limit := c.EventStream[last].Time + options.Threshold
+	for _, ev := range c.EventStream[last+1:] {
+		if ev.Time > limit {
+			ev.Time = limit
+		}
+	}
+
+	shift := c.EventStream[first].Time - options.Threshold
+	if shift > 0 {
+		for _, ev := range c.EventStream {
+			ev.Time -= shift
+			if ev.Time < 0 {
+				ev.Time = 0
+			}
+		}
+	}
+
+	roundTimes(c.EventStream)
+	return
+}
+
+// isVisible verifies whether an event prints something that ends up
+// visible in the terminal.
+func isVisible(ev *cast.Event) bool {
+	if ev.Type != "o" {
+		return false
+	}
+
+	for _, r := range ansi.Strip(ev.Data) {
+		if !unicode.IsSpace(r) && !unicode.IsControl(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// finalExitIndex finds the position of the event where the final `exit`
+// command starts being echoed, returning -1 if the output doesn't end
+// with such command.
+//
+// The last line of the output must be made of the prompt followed by
+// `exit` (and nothing but whitespace), where the prompt is whatever got
+// printed on that line before `exit` started being echoed.
+//
+// To deal with shells that redraw what's being typed (e.g., `e`, `\bex`,
+// `i`, `t`), the output is interpreted as lines of text where carriage
+// returns and backspaces move the cursor back, tracking the last event
+// that changed each position (redrawing the same character keeps the
+// event that wrote it first).
+func finalExitIndex(c *cast.Cast) int {
+	var (
+		text      = make([]byte, 0)
+		owners    = make([]int, 0)
+		cursor    int
+		lineStart int
+	)
+
+	for idx, ev := range c.EventStream {
+		if ev.Type != "o" {
+			continue
+		}
+
+		for _, token := range ansi.Tokenize(ev.Data) {
+			if token.Kind == ansi.Escape {
+				continue
+			}
+
+			for i := 0; i < len(token.Data); i++ {
+				b := token.Data[i]
+
+				switch {
+				case b == '\r':
+					cursor = lineStart
+				case b == '\b':
+					if cursor > lineStart {
+						cursor--
+					}
+				case b == '\n':
+					text = append(text, '\n')
+					owners = append(owners, idx)
+					cursor = len(text)
+					lineStart = cursor
+				case token.Kind == ansi.Control:
+				case cursor < len(text):
+					if text[cursor] != b {
+						text[cursor] = b
+						owners[cursor] = idx
+					}
+					cursor++
+				default:
+					text = append(text, b)
+					owners = append(owners, idx)
+					cursor++
+				}
+			}
+		}
+	}
+
+	var (
+		content = strings.TrimRightFunc(string(text), unicode.IsSpace)
+		start   = strings.LastIndexByte(content, '\n') + 1
+		pos     = len(content) - len("exit")
+	)
+
+	if pos < start || content[pos:] != "exit" {
+		return -1
+	}
+
+	if pos > start && !unicode.IsSpace(rune(content[pos-1])) {
+		return -1
+	}
+
+	// the prompt must have been printed before the command got typed,
+	// otherwise the line is just output that happens to end in "exit".
+	for i := start; i < pos; i++ {
+		if owners[i] >= owners[pos] {
+			return -1
+		}
+	}
+
+	return owners[pos]
+}
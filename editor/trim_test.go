@@ -0,0 +1,194 @@
+package editor_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Trim", func() {
+	Describe("parameter validation", func() {
+		It("fails with nil cast", func() {
+			err := editor.Trim(nil, editor.TrimOptions{})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with empty event stream", func() {
+			err := editor.Trim(&cast.Cast{}, editor.TrimOptions{})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with negative threshold", func() {
+			err := editor.Trim(&cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1, Type: "o", Data: "a"},
+				},
+			}, editor.TrimOptions{Threshold: -1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails without visible output", func() {
+			err := editor.Trim(&cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1, Type: "o", Data: "\x1b]2;title\x07"},
+					{Time: 2, Type: "o", Data: " \r\n"},
+				},
+			}, editor.TrimOptions{})
+			Expect(err).ToNot(Succeed())
+		})
+	})
+
+	Context("with idle time at both ends", func() {
+		var (
+			data    *cast.Cast
+			options editor.TrimOptions
+		)
+
+		BeforeEach(func() {
+			options = editor.TrimOptions{}
+			data = &cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1, Type: "o", Data: "\x1b]2;title\x07"},
+					{Time: 4, Type: "o", Data: "\x1b[1m$ "},
+					{Time: 5, Type: "o", Data: "ls"},
+					{Time: 6, Type: "o", Data: "\r\n"},
+					{Time: 9, Type: "i", Data: "\x04"},
+					{Time: 9, Type: "o", Data: "\x1b[0m"},
+				},
+			}
+		})
+
+		It("removes it, rebasing the timestamps", func() {
+			Expect(editor.Trim(data, options)).To(Succeed())
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				{Time: 0, Type: "o", Data: "\x1b]2;title\x07"},
+				{Time: 0, Type: "o", Data: "\x1b[1m$ "},
+				{Time: 1, Type: "o", Data: "ls"},
+				{Time: 1, Type: "o", Data: "\r\n"},
+				{Time: 1, Type: "i", Data: "\x04"},
+				{Time: 1, Type: "o", Data: "\x1b[0m"},
+			}))
+		})
+
+		It("keeps idle time up to the threshold", func() {
+			options.Threshold = 0.5
+			Expect(editor.Trim(data, options)).To(Succeed())
+
+			times := []float64{}
+			for _, ev := range data.EventStream {
+				times = append(times, ev.Time)
+			}
+
+			Expect(times).To(Equal([]float64{0, 0.5, 1.5, 2, 2, 2}))
+		})
+	})
+
+	It("rounds clamped timestamps even without leading idle time", func() {
+		data := &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "o", Data: "$ "},
+				{Time: 0.1, Type: "o", Data: "ls"},
+				{Time: 9, Type: "o", Data: "\r\n"},
+			},
+		}
+
+		Expect(editor.Trim(data, editor.TrimOptions{
+			Threshold: 0.2,
+		})).To(Succeed())
+		Expect(data.EventStream[2].Time).To(Equal(0.3))
+	})
+
+	Context("with a final exit command", func() {
+		var data *cast.Cast
+
+		BeforeEach(func() {
+			data = &cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1, Type: "o", Data: "$ "},
+					{Time: 2, Type: "o", Data: "ls\r\nfile\r\n$ "},
+					{Time: 3, Type: "o", Data: "e"},
+					{Time: 4, Type: "o", Data: "\bex"},
+					{Time: 5, Type: "o", Data: "it"},
+					{Time: 6, Type: "o", Data: "\r\n"},
+				},
+			}
+		})
+
+		It("keeps it by default", func() {
+			Expect(editor.Trim(data, editor.TrimOptions{})).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(6))
+		})
+
+		It("removes it if asked to", func() {
+			Expect(editor.Trim(data, editor.TrimOptions{
+				RemoveExit: true,
+			})).To(Succeed())
+			Expect(data.EventStream).To(Equal([]*cast.Event{
+				{Time: 0, Type: "o", Data: "$ "},
+				{Time: 1, Type: "o", Data: "ls\r\nfile\r\n$ "},
+			}))
+		})
+
+		It("doesn't remove exit if it's not the last command", func() {
+			data.EventStream = append(data.EventStream,
+				&cast.Event{Time: 7, Type: "o", Data: "$ "})
+			Expect(editor.Trim(data, editor.TrimOptions{
+				RemoveExit: true,
+			})).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(7))
+		})
+
+		It("doesn't remove output that ends in exit", func() {
+			data.EventStream = []*cast.Event{
+				{Time: 1, Type: "o", Data: "make\r\n"},
+				{Time: 2, Type: "o", Data: "Press q to exit"},
+				{Time: 3, Type: "o", Data: ""},
+			}
+
+			Expect(editor.Trim(data, editor.TrimOptions{
+				RemoveExit: true,
+			})).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(3))
+			Expect(data.EventStream[1].Data).To(Equal("Press q to exit"))
+		})
+
+		It("finds exit typed over the padding of a previous event", func() {
+			var (
+				padding = strings.Repeat(" ", 80)
+				prompt  = "\r\x1b[0m\x1b[J\x1b[01;32m \x1b[36masciinema-edit " +
+					"\x1b[00m\x1b[K"
+			)
+
+			data.EventStream = []*cast.Event{
+				{Time: 10.106916, Type: "o", Data: "t"},
+				{Time: 10.131093, Type: "o", Data: "\bti"},
+				{Time: 10.178991, Type: "o", Data: "m"},
+				{Time: 11.674998, Type: "o", Data: "e"},
+				{Time: 12.170331, Type: "o", Data: "\x1b[?2004l\r\r\n"},
+				{Time: 12.171527, Type: "o", Data: "shell  0.16s user 0.08s " +
+					"system 2% cpu 12.159 total\r\n" +
+					"\x1b[1m\x1b[7m%\x1b[27m\x1b[1m\x1b[0m" + padding +
+					"\r \r"},
+				{Time: 12.215481, Type: "o", Data: prompt},
+				{Time: 12.215655, Type: "o", Data: "\x1b[?1h\x1b=\x1b[?2004h"},
+				{Time: 12.65896, Type: "o", Data: "e"},
+				{Time: 12.826927, Type: "o", Data: "\bex"},
+				{Time: 12.954333, Type: "o", Data: "i"},
+				{Time: 13.186748, Type: "o", Data: "t"},
+				{Time: 13.410217, Type: "o", Data: "\x1b[?2004l\r\r\n"},
+				{Time: 13.411091, Type: "o", Data: "\x1b]2;exit\x07"},
+			}
+
+			Expect(editor.Trim(data, editor.TrimOptions{
+				RemoveExit: true,
+			})).To(Succeed())
+			Expect(data.EventStream).To(HaveLen(8))
+			Expect(data.EventStream[5].Data).To(ContainSubstring("total"))
+			Expect(data.EventStream[6].Data).To(Equal(prompt))
+		})
+	})
+})
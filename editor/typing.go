@@ -0,0 +1,258 @@
+package editor
+
+import (
+	"math/rand"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/stats"
+	"github.com/pkg/errors"
+)
+
+// TypingStreak is a sequence of keystrokes typed without pausing.
+type TypingStreak struct {
+	// Start is the position of the first event of the streak.
+	Start int
+	// End is the position right after the last event of the streak.
+	End int
+	// Keys contains the positions of the events that mark each
+	// keystroke: the input events (`i`) if the streak has any, or
+	// the echoed characters (`o`) otherwise.
+	Keys []int
+}
+
+// TypingStreaks detects the sequences of keystrokes in a cast.
+//
+// A keystroke is an input or output event that carries a single
+// printable character (besides escape sequences), like the ones
+// produced by a shell echoing what's being typed. Consecutive
+// keystrokes that happen at most `stats.TypingPause` seconds apart
+// form a streak, as long as there are at least two of them.
+//
+// When input events were recorded, they determine the cadence, with
+// the echoes following them.
+func TypingStreaks(c *cast.Cast) (streaks []TypingStreak) {
+	streaks = make([]TypingStreak, 0)
+
+	var start = 0
+
+	for start < len(c.EventStream) {
+		if !isKeystroke(c.EventStream[start]) {
+			start++
+			continue
+		}
+
+		end := start + 1
+		for end < len(c.EventStream) &&
+			isKeystroke(c.EventStream[end]) &&
+			c.EventStream[end].Time-c.EventStream[end-1].Time <= stats.TypingPause {
+			end++
+		}
+
+		if streak, ok := typingStreak(c, start, end); ok {
+			streaks = append(streaks, streak)
+		}
+
+		start = end
+	}
+
+	return
+}
+
+// typingStreak builds a streak out of the keystrokes in `[start, end)`.
+func typingStreak(c *cast.Cast, start, end int) (streak TypingStreak, ok bool) {
+	var keyType = "o"
+
+	for _, ev := range c.EventStream[start:end] {
+		if ev.Type == "i" {
+			keyType = "i"
+			break
+		}
+	}
+
+	streak.Keys = make([]int, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		if c.EventStream[idx].Type == keyType {
+			streak.Keys = append(streak.Keys, idx)
+		}
+	}
+
+	if len(streak.Keys) < 2 {
+		return
+	}
+
+	streak.Start = streak.Keys[0]
+	streak.End = end
+	ok = true
+	return
+}
+
+// isKeystroke verifies whether an event carries a single printable
+// character.
+func isKeystroke(ev *cast.Event) bool {
+	if ev.Type != "i" && ev.Type != "o" {
+		return false
+	}
+
+	text := ansi.Strip(ev.Data)
+	if utf8.RuneCountInString(text) != 1 {
+		return false
+	}
+
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsPrint(r)
+}
+
+// HumanizeOptions configures how `Humanize` changes the typing cadence.
+type HumanizeOptions struct {
+	// Jitter is the maximum variation of each delay between
+	// keystrokes, relative to the average delay of the streak (e.g.,
+	// `0.5` makes delays vary from 50% to 150% of the average).
+	Jitter float64
+
+	// Seed initializes the random number generator, making the
+	// results reproducible.
+	Seed int64
+}
+
+// Humanize redistributes the keystrokes of each typing streak with a
+// random per-character jitter around the average delay of the streak,
+// making scripted typing look less robotic.
+//
+// The total duration of each streak is kept, thus the events that
+// follow are not affected.
+//
+// Returns the number of streaks that got changed.
+func Humanize(c *cast.Cast, options HumanizeOptions) (count int, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if options.Jitter < 0 || options.Jitter >= 1 {
+		err = errors.Errorf("jitter must be within 0 (included) and 1 (excluded)")
+		return
+	}
+
+	var random = rand.New(rand.NewSource(options.Seed))
+
+	count = retype(c, func(delays []float64) []float64 {
+		var (
+			total   float64
+			jittery float64
+			res     = make([]float64, len(delays))
+		)
+
+		for idx, delay := range delays {
+			total += delay
+			res[idx] = 1 + options.Jitter*(2*random.Float64()-1)
+			jittery += res[idx]
+		}
+
+		for idx := range res {
+			res[idx] *= total / jittery
+		}
+
+		return res
+	})
+
+	return
+}
+
+// NormalizeTyping makes all typing streaks happen at a fixed rate of
+// `cps` characters per second, delaying (or advancing) the events that
+// follow each streak accordingly.
+//
+// Returns the number of streaks that got changed.
+func NormalizeTyping(c *cast.Cast, cps float64) (count int, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if cps <= 0 {
+		err = errors.Errorf("characters per second must be positive")
+		return
+	}
+
+	count = retype(c, func(delays []float64) []float64 {
+		res := make([]float64, len(delays))
+
+		for idx := range res {
+			res[idx] = 1 / cps
+		}
+
+		return res
+	})
+
+	return
+}
+
+// retype replaces the delays between the keystrokes of each typing
+// streak by the ones computed by `cadence`, which receives the
+// original delays.
+//
+// Events in a streak that are not keystrokes (e.g., echoes) keep their
+// offset to the keystroke that precedes them, while the events after a
+// streak are shifted by how much its end moved.
+func retype(c *cast.Cast, cadence func(delays []float64) []float64) (count int) {
+	var (
+		streaks = TypingStreaks(c)
+		times   = make([]float64, len(c.EventStream))
+		shift   float64
+		next    int
+	)
+
+	for idx, ev := range c.EventStream {
+		times[idx] = ev.Time
+	}
+
+	for _, streak := range streaks {
+		for ; next < streak.Start; next++ {
+			c.EventStream[next].Time = times[next] + shift
+		}
+
+		var (
+			keys   = streak.Keys
+			delays = make([]float64, len(keys)-1)
+			newKey = make([]float64, len(keys))
+		)
+
+		for idx := range delays {
+			delays[idx] = times[keys[idx+1]] - times[keys[idx]]
+		}
+
+		newKey[0] = times[keys[0]] + shift
+		for idx, delay := range cadence(delays) {
+			newKey[idx+1] = newKey[idx] + delay
+		}
+
+		for key := range keys {
+			last := streak.End
+			if key+1 < len(keys) {
+				last = keys[key+1]
+			}
+
+			for ; next < last; next++ {
+				t := newKey[key] + times[next] - times[keys[key]]
+				if key+1 < len(keys) && t > newKey[key+1] {
+					t = newKey[key+1]
+				}
+
+				c.EventStream[next].Time = t
+			}
+		}
+
+		shift = newKey[len(keys)-1] - times[keys[len(keys)-1]]
+		count++
+	}
+
+	for ; next < len(c.EventStream); next++ {
+		c.EventStream[next].Time = times[next] + shift
+	}
+
+	roundTimes(c.EventStream)
+	return
+}
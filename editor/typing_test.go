@@ -0,0 +1,151 @@
+package editor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/editor"
+)
+
+var _ = Describe("Typing", func() {
+	var (
+		data  *cast.Cast
+		count int
+		err   error
+	)
+
+	BeforeEach(func() {
+		data = &cast.Cast{
+			EventStream: []*cast.Event{
+				{Time: 0, Type: "o", Data: "$ "},
+				{Time: 1, Type: "o", Data: "l"},
+				{Time: 1.2, Type: "o", Data: "s"},
+				{Time: 1.4, Type: "o", Data: " "},
+				{Time: 1.6, Type: "o", Data: "-"},
+				{Time: 1.8, Type: "o", Data: "l"},
+				{Time: 3, Type: "o", Data: "\r\n"},
+				{Time: 4, Type: "o", Data: "file\r\n"},
+			},
+		}
+	})
+
+	Describe("TypingStreaks", func() {
+		It("detects echoed keystrokes", func() {
+			Expect(editor.TypingStreaks(data)).To(Equal([]editor.TypingStreak{
+				{Start: 1, End: 6, Keys: []int{1, 2, 3, 4, 5}},
+			}))
+		})
+
+		It("breaks streaks at pauses", func() {
+			data.EventStream[3].Time = 2.5
+			data.EventStream[4].Time = 2.6
+			data.EventStream[5].Time = 2.8
+
+			Expect(editor.TypingStreaks(data)).To(Equal([]editor.TypingStreak{
+				{Start: 1, End: 3, Keys: []int{1, 2}},
+				{Start: 3, End: 6, Keys: []int{3, 4, 5}},
+			}))
+		})
+
+		It("uses input events as keystrokes when present", func() {
+			data.EventStream = []*cast.Event{
+				{Time: 0, Type: "i", Data: "l"},
+				{Time: 0.01, Type: "o", Data: "l"},
+				{Time: 0.2, Type: "i", Data: "s"},
+				{Time: 0.21, Type: "o", Data: "s"},
+			}
+
+			Expect(editor.TypingStreaks(data)).To(Equal([]editor.TypingStreak{
+				{Start: 0, End: 4, Keys: []int{0, 2}},
+			}))
+		})
+	})
+
+	Describe("Humanize", func() {
+		It("fails with invalid jitter", func() {
+			_, err = editor.Humanize(data, editor.HumanizeOptions{Jitter: 1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("varies the delays keeping the streak duration", func() {
+			count, err = editor.Humanize(data, editor.HumanizeOptions{
+				Jitter: 0.5,
+				Seed:   42,
+			})
+			Expect(err).To(Succeed())
+			Expect(count).To(Equal(1))
+
+			res := times(data)
+			Expect(res[0]).To(Equal(0.0))
+			Expect(res[1]).To(Equal(1.0))
+			Expect(res[5]).To(BeNumerically("~", 1.8, 1e-9))
+			Expect(res[6:]).To(Equal([]float64{3, 4}))
+
+			var different bool
+			for idx := 1; idx < 5; idx++ {
+				delay := res[idx+1] - res[idx]
+				Expect(delay).To(BeNumerically(">", 0))
+				Expect(delay).To(BeNumerically("<", 0.8))
+				different = different || delay < 0.19 || delay > 0.21
+			}
+			Expect(different).To(BeTrue())
+		})
+
+		It("is reproducible", func() {
+			another := &cast.Cast{EventStream: []*cast.Event{}}
+			for _, ev := range data.EventStream {
+				copied := *ev
+				another.EventStream = append(another.EventStream, &copied)
+			}
+
+			options := editor.HumanizeOptions{Jitter: 0.3, Seed: 7}
+			_, err = editor.Humanize(data, options)
+			Expect(err).To(Succeed())
+			_, err = editor.Humanize(another, options)
+			Expect(err).To(Succeed())
+
+			for idx, ev := range another.EventStream {
+				Expect(ev.Time).To(Equal(data.EventStream[idx].Time))
+			}
+		})
+	})
+
+	Describe("NormalizeTyping", func() {
+		It("fails with non-positive rate", func() {
+			_, err = editor.NormalizeTyping(data, 0)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("types at a fixed rate shifting the following events", func() {
+			count, err = editor.NormalizeTyping(data, 10)
+			Expect(err).To(Succeed())
+			Expect(count).To(Equal(1))
+
+			res := times(data)
+			expected := []float64{0, 1, 1.1, 1.2, 1.3, 1.4, 2.6, 3.6}
+			for idx := range expected {
+				Expect(res[idx]).To(BeNumerically("~", expected[idx], 1e-9))
+			}
+		})
+
+		It("keeps echoes right after their input", func() {
+			data.EventStream = []*cast.Event{
+				{Time: 0, Type: "i", Data: "l"},
+				{Time: 0.01, Type: "o", Data: "l"},
+				{Time: 0.5, Type: "i", Data: "s"},
+				{Time: 0.51, Type: "o", Data: "s"},
+				{Time: 1, Type: "o", Data: "\r\n"},
+			}
+
+			_, err = editor.NormalizeTyping(data, 5)
+			Expect(err).To(Succeed())
+
+			res := times(data)
+			expected := []float64{0, 0.01, 0.2, 0.21, 0.7}
+			for idx := range expected {
+				Expect(res[idx]).To(BeNumerically("~", expected[idx], 1e-9))
+			}
+		})
+	})
+})
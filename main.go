@@ -26,9 +26,30 @@ func main() {
 	app.Description = `asciinema-edit provides missing features from the "asciinema" tool
    when it comes to editing a cast that has already been recorded.`
 	app.Commands = []cli.Command{
+		commands.BakeIdle,
+		commands.Blank,
+		commands.Compact,
+		commands.Concat,
 		commands.Cut,
+		commands.Dedupe,
+		commands.Fit,
+		commands.Fix,
+		commands.Header,
+		commands.Humanize,
+		commands.Info,
+		commands.Insert,
+		commands.Lint,
+		commands.NormalizeTyping,
+		commands.Pause,
 		commands.Quantize,
+		commands.Rebase,
+		commands.Repeat,
+		commands.Shift,
 		commands.Speed,
+		commands.Split,
+		commands.Spread,
+		commands.Strip,
+		commands.Trim,
 	}
 
 	app.Run(os.Args)
@@ -0,0 +1,253 @@
+// Package stats provides a set of functions to extract information
+// from asciinema casts, such as their duration and the distribution
+// of the delays between events.
+package stats
+
+import (
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/pkg/errors"
+)
+
+// TypingPause is the maximum delay between two consecutive input
+// events for them to be considered part of the same typing streak.
+//
+// Delays bigger than that are considered pauses (e.g., the user
+// thinking or waiting for a command to finish) and do not account
+// for the typing speed.
+const TypingPause = 1.0
+
+// Gap represents a period of idleness between two consecutive events.
+type Gap struct {
+	// Index is the position in the event stream of the event that
+	// ends the gap.
+	Index int
+
+	// Time is the timestamp of the event that starts the gap.
+	Time float64
+
+	// Duration is the amount of time (in seconds) between the event
+	// that starts the gap and the one that ends it.
+	Duration float64
+}
+
+// Summary aggregates information about a cast.
+type Summary struct {
+	// Duration is the timestamp of the last event in the event
+	// stream.
+	Duration float64
+
+	// Events is the total number of events in the event stream.
+	Events int
+
+	// EventsByType holds the number of events per event type.
+	EventsByType map[string]int
+
+	// Bytes is the total number of bytes carried in the data of
+	// the events.
+	Bytes int
+
+	// BytesByType holds the number of bytes per event type.
+	BytesByType map[string]int
+
+	// LongestGaps contains the longest periods of idleness in the
+	// event stream, sorted from the longest to the shortest.
+	LongestGaps []Gap
+
+	// TypedChars is the number of characters captured by input
+	// events.
+	TypedChars int
+
+	// TypingTime is the amount of time (in seconds) spent typing,
+	// excluding pauses longer than `TypingPause`.
+	TypingTime float64
+
+	// Delays contains the delays between each pair of consecutive
+	// events.
+	Delays []float64
+}
+
+// CharsPerSecond is the effective typing speed computed from the
+// input events.
+func (s *Summary) CharsPerSecond() float64 {
+	if s.TypingTime == 0 {
+		return 0
+	}
+
+	return float64(s.TypedChars) / s.TypingTime
+}
+
+// WordsPerMinute is the effective typing speed computed from the
+// input events considering the standard word length of five
+// characters.
+func (s *Summary) WordsPerMinute() float64 {
+	return s.CharsPerSecond() * 60 / 5
+}
+
+// Summarize gathers information about the cast, keeping the `gaps`
+// longest periods of idleness.
+//
+// It assumes that the provided `cast` is entirely valid (see
+// `github.com/cirocosta/asciinema-edit/cast#Validate`).
+func Summarize(c *cast.Cast, gaps int) (s *Summary, err error) {
+	if c == nil {
+		err = errors.Errorf("cast must not be nil")
+		return
+	}
+
+	if gaps < 0 {
+		err = errors.Errorf("number of gaps must not be negative")
+		return
+	}
+
+	s = &Summary{
+		EventsByType: make(map[string]int),
+		BytesByType:  make(map[string]int),
+		Delays:       Delays(c),
+		LongestGaps:  make([]Gap, 0),
+	}
+
+	var lastInput *cast.Event
+
+	for idx, ev := range c.EventStream {
+		s.Events++
+		s.EventsByType[ev.Type]++
+		s.Bytes += len(ev.Data)
+		s.BytesByType[ev.Type] += len(ev.Data)
+		s.Duration = ev.Time
+
+		if idx > 0 {
+			s.LongestGaps = append(s.LongestGaps, Gap{
+				Index:    idx,
+				Time:     c.EventStream[idx-1].Time,
+				Duration: s.Delays[idx-1],
+			})
+		}
+
+		if ev.Type != "i" {
+			continue
+		}
+
+		s.TypedChars += utf8.RuneCountInString(ev.Data)
+		if lastInput != nil && ev.Time-lastInput.Time <= TypingPause {
+			s.TypingTime += ev.Time - lastInput.Time
+		}
+
+		lastInput = ev
+	}
+
+	sort.SliceStable(s.LongestGaps, func(i, j int) bool {
+		return s.LongestGaps[i].Duration > s.LongestGaps[j].Duration
+	})
+
+	if len(s.LongestGaps) > gaps {
+		s.LongestGaps = s.LongestGaps[:gaps]
+	}
+
+	return
+}
+
+// Delays computes the delays between each pair of consecutive events
+// in the event stream.
+func Delays(c *cast.Cast) (delays []float64) {
+	delays = make([]float64, 0)
+
+	if c == nil || len(c.EventStream) == 0 {
+		return
+	}
+
+	for i := 0; i < len(c.EventStream)-1; i++ {
+		delays = append(delays,
+			c.EventStream[i+1].Time-c.EventStream[i].Time)
+	}
+
+	return
+}
+
+// Percentile computes the p-th percentile (0 <= p <= 100) of a set of
+// values using the nearest-rank method.
+//
+// The provided slice is not modified.
+func Percentile(values []float64, p float64) (res float64, err error) {
+	if len(values) == 0 {
+		err = errors.Errorf("values must not be empty")
+		return
+	}
+
+	if p < 0 || p > 100 {
+		err = errors.Errorf("percentile must be within 0 and 100")
+		return
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	res = sorted[rank-1]
+	return
+}
+
+// Bucket represents a histogram bucket that counts the values that lie
+// in `[From, To)`.
+type Bucket struct {
+	From  float64
+	To    float64
+	Count int
+}
+
+// DefaultHistogramBounds are the bucket boundaries (in seconds) used
+// for histograms of delays.
+//
+// Delays between events tend to span several orders of magnitude (from
+// microseconds when a program writes its output to several seconds when
+// the user pauses), thus the boundaries grow roughly exponentially.
+var DefaultHistogramBounds = []float64{
+	0, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10,
+}
+
+// Histogram counts how many values lie in each of the buckets delimited
+// by `bounds`.
+//
+// `bounds` must be sorted in increasing order. The last bucket is
+// unbounded (goes from the last bound to infinity), while values smaller
+// than the first bound are not counted.
+func Histogram(values []float64, bounds []float64) (buckets []Bucket, err error) {
+	if len(bounds) == 0 {
+		err = errors.Errorf("at least one bound must be specified")
+		return
+	}
+
+	if !sort.Float64sAreSorted(bounds) {
+		err = errors.Errorf("bounds must be sorted")
+		return
+	}
+
+	buckets = make([]Bucket, len(bounds))
+	for idx, bound := range bounds {
+		buckets[idx].From = bound
+		buckets[idx].To = math.MaxFloat64
+
+		if idx+1 < len(bounds) {
+			buckets[idx].To = bounds[idx+1]
+		}
+	}
+
+	for _, value := range values {
+		for idx := range buckets {
+			if value >= buckets[idx].From && value < buckets[idx].To {
+				buckets[idx].Count++
+				break
+			}
+		}
+	}
+
+	return
+}
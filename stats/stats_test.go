@@ -0,0 +1,160 @@
+package stats_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/cast"
+	"github.com/cirocosta/asciinema-edit/stats"
+)
+
+var _ = Describe("Stats", func() {
+	Describe("Summarize", func() {
+		Context("with nil cast", func() {
+			It("fails", func() {
+				_, err := stats.Summarize(nil, 1)
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with negative number of gaps", func() {
+			It("fails", func() {
+				_, err := stats.Summarize(&cast.Cast{}, -1)
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("with non-empty event stream", func() {
+			var (
+				summary *stats.Summary
+				err     error
+				data    *cast.Cast
+			)
+
+			BeforeEach(func() {
+				data = &cast.Cast{
+					EventStream: []*cast.Event{
+						{Time: 1, Type: "o", Data: "$ "},
+						{Time: 3, Type: "i", Data: "l"},
+						{Time: 3.5, Type: "i", Data: "s"},
+						{Time: 4, Type: "i", Data: "\r"},
+						{Time: 9, Type: "o", Data: "ãb"},
+						{Time: 10, Type: "o", Data: "$ "},
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				summary, err = stats.Summarize(data, 2)
+				Expect(err).To(Succeed())
+			})
+
+			It("has the duration of the last event", func() {
+				Expect(summary.Duration).To(Equal(float64(10)))
+			})
+
+			It("counts events per type", func() {
+				Expect(summary.Events).To(Equal(6))
+				Expect(summary.EventsByType).To(Equal(map[string]int{
+					"o": 3,
+					"i": 3,
+				}))
+			})
+
+			It("counts bytes per type", func() {
+				Expect(summary.Bytes).To(Equal(10))
+				Expect(summary.BytesByType).To(Equal(map[string]int{
+					"o": 7,
+					"i": 3,
+				}))
+			})
+
+			It("keeps only the longest gaps sorted", func() {
+				Expect(summary.LongestGaps).To(Equal([]stats.Gap{
+					{Index: 4, Time: 4, Duration: 5},
+					{Index: 1, Time: 1, Duration: 2},
+				}))
+			})
+
+			It("computes the typing speed ignoring pauses", func() {
+				Expect(summary.TypedChars).To(Equal(3))
+				Expect(summary.TypingTime).To(Equal(float64(1)))
+				Expect(summary.CharsPerSecond()).To(Equal(float64(3)))
+				Expect(summary.WordsPerMinute()).To(Equal(float64(36)))
+			})
+		})
+	})
+
+	Describe("Delays", func() {
+		It("is empty with an empty event stream", func() {
+			Expect(stats.Delays(&cast.Cast{})).To(BeEmpty())
+		})
+
+		It("computes the delays between consecutive events", func() {
+			Expect(stats.Delays(&cast.Cast{
+				EventStream: []*cast.Event{
+					{Time: 1}, {Time: 2}, {Time: 5},
+				},
+			})).To(Equal([]float64{1, 3}))
+		})
+	})
+
+	Describe("Percentile", func() {
+		var values = []float64{5, 1, 4, 2, 3}
+
+		It("fails with empty values", func() {
+			_, err := stats.Percentile([]float64{}, 50)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with percentile out of range", func() {
+			_, err := stats.Percentile(values, 101)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("computes using the nearest rank", func() {
+			res, err := stats.Percentile(values, 50)
+			Expect(err).To(Succeed())
+			Expect(res).To(Equal(float64(3)))
+
+			res, err = stats.Percentile(values, 100)
+			Expect(err).To(Succeed())
+			Expect(res).To(Equal(float64(5)))
+
+			res, err = stats.Percentile(values, 0)
+			Expect(err).To(Succeed())
+			Expect(res).To(Equal(float64(1)))
+		})
+
+		It("doesn't modify the values", func() {
+			_, err := stats.Percentile(values, 50)
+			Expect(err).To(Succeed())
+			Expect(values).To(Equal([]float64{5, 1, 4, 2, 3}))
+		})
+	})
+
+	Describe("Histogram", func() {
+		It("fails without bounds", func() {
+			_, err := stats.Histogram([]float64{1}, nil)
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("fails with unsorted bounds", func() {
+			_, err := stats.Histogram([]float64{1}, []float64{2, 1})
+			Expect(err).ToNot(Succeed())
+		})
+
+		It("counts the values in each bucket", func() {
+			buckets, err := stats.Histogram(
+				[]float64{0.5, 1, 1.5, 3, 100},
+				[]float64{1, 2})
+			Expect(err).To(Succeed())
+			Expect(buckets).To(Equal([]stats.Bucket{
+				{From: 1, To: 2, Count: 2},
+				{From: 2, To: math.MaxFloat64, Count: 2},
+			}))
+		})
+	})
+})
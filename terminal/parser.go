@@ -0,0 +1,458 @@
+package terminal
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cirocosta/asciinema-edit/ansi"
+)
+
+// Write interprets a piece of output, updating the screen.
+//
+// Output might end in the middle of an escape sequence or of a UTF-8
+// character, in which case the rest is expected in the next write.
+func (t *Terminal) Write(data string) {
+	data = t.pending + data
+	t.pending = ""
+
+	tokens := ansi.Tokenize(data)
+	if len(tokens) == 0 {
+		return
+	}
+
+	last := tokens[len(tokens)-1]
+	if last.Incomplete {
+		t.pending = last.Data
+		tokens = tokens[:len(tokens)-1]
+	} else if last.Kind == ansi.Text {
+		if suffix := incompleteRune(last.Data); suffix != "" {
+			t.pending = suffix
+			tokens[len(tokens)-1].Data = last.Data[:len(last.Data)-len(suffix)]
+		}
+	}
+
+	for _, token := range tokens {
+		switch token.Kind {
+		case ansi.Text:
+			for _, r := range token.Data {
+				t.print(r)
+			}
+		case ansi.Control:
+			t.control(token.Data[0])
+		case ansi.Escape:
+			t.escape(token.Data)
+		}
+	}
+}
+
+// incompleteRune retrieves the trailing bytes of `data` that start a
+// UTF-8 character without finishing it.
+func incompleteRune(data string) string {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+
+		if utf8.FullRuneInString(data[i:]) {
+			return ""
+		}
+
+		return data[i:]
+	}
+
+	return ""
+}
+
+// control interprets a C0 control character.
+func (t *Terminal) control(b byte) {
+	switch b {
+	case '\r':
+		t.cursor.x = 0
+		t.wrapNext = false
+	case '\n', '\v', '\f':
+		t.lineFeed()
+	case '\b':
+		if t.cursor.x > 0 {
+			t.cursor.x--
+		}
+		t.wrapNext = false
+	case '\t':
+		t.tab()
+	case 0x0e:
+		t.shifted = true
+	case 0x0f:
+		t.shifted = false
+	case 0x00, '\a':
+	default:
+		t.unhandled++
+	}
+}
+
+// escape interprets an escape sequence.
+func (t *Terminal) escape(data string) {
+	if len(data) < 2 {
+		return
+	}
+
+	switch data[1] {
+	case '[':
+		t.controlSequence(data[2:])
+	case ']':
+		t.operatingSystemCommand(data[2:])
+	case '(', ')':
+		if len(data) != 3 {
+			t.unhandled++
+			return
+		}
+
+		t.charsets[data[1]-'('] = data[2]
+	case '=', '>':
+		// keypad modes only affect the input.
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.cursor.x = 0
+		t.lineFeed()
+	case 'M':
+		t.reverseIndex()
+	case 'c':
+		t.reset(t.width, t.height)
+	default:
+		t.unhandled++
+	}
+}
+
+// operatingSystemCommand interprets the body of an operating system
+// command (what comes after `ESC ]`).
+//
+// Only the ones that are known not to affect the screen are accepted:
+// window and icon titles (0, 1 and 2) and the working directory (7).
+func (t *Terminal) operatingSystemCommand(body string) {
+	command := body
+	if idx := strings.IndexByte(body, ';'); idx != -1 {
+		command = body[:idx]
+	}
+
+	switch command {
+	case "0", "1", "2", "7":
+	default:
+		t.unhandled++
+	}
+}
+
+// controlSequence interprets the body of a control sequence (what
+// comes after `ESC [`).
+func (t *Terminal) controlSequence(body string) {
+	if body == "" {
+		t.unhandled++
+		return
+	}
+
+	final := body[len(body)-1]
+	if final < 0x40 || final > 0x7e {
+		t.unhandled++
+		return
+	}
+
+	body = body[:len(body)-1]
+
+	var private byte
+	if body != "" && strings.IndexByte("?<=>", body[0]) != -1 {
+		private = body[0]
+		body = body[1:]
+	}
+
+	if strings.IndexFunc(body, func(r rune) bool {
+		return r < '0' || r > ';'
+	}) != -1 {
+		// intermediate bytes (e.g., `CSI SP q`) introduce
+		// functions that are not supported.
+		t.unhandled++
+		return
+	}
+
+	params := parseParams(body)
+
+	if private != 0 {
+		if private != '?' || (final != 'h' && final != 'l') {
+			t.unhandled++
+			return
+		}
+
+		for _, mode := range params {
+			t.setPrivateMode(mode, final == 'h')
+		}
+		return
+	}
+
+	var (
+		n = param(params, 0, 1)
+		x = t.cursor.x
+		y = t.cursor.y
+	)
+
+	switch final {
+	case '@':
+		t.insertCells(n)
+	case 'A':
+		t.moveTo(x, clampUp(y, n, t.top))
+	case 'B', 'e':
+		t.moveTo(x, clampDown(y, n, t.bottom, t.height))
+	case 'C', 'a':
+		t.moveTo(x+n, y)
+	case 'D':
+		t.moveTo(x-n, y)
+	case 'E':
+		t.moveTo(0, clampDown(y, n, t.bottom, t.height))
+	case 'F':
+		t.moveTo(0, clampUp(y, n, t.top))
+	case 'G', '`':
+		t.moveTo(n-1, y)
+	case 'H', 'f':
+		t.moveTo(param(params, 1, 1)-1, n-1)
+	case 'J':
+		t.eraseDisplay(param(params, 0, 0))
+	case 'K':
+		t.eraseLine(param(params, 0, 0))
+	case 'L':
+		t.insertRows(y, n)
+		t.moveTo(0, y)
+	case 'M':
+		t.deleteRows(y, n)
+		t.moveTo(0, y)
+	case 'P':
+		t.deleteCells(n)
+		t.wrapNext = false
+	case 'S':
+		t.scrollUp(n)
+	case 'T':
+		t.scrollDown(n)
+	case 'X':
+		t.erase(y, x, x+n)
+		t.wrapNext = false
+	case 'd':
+		t.moveTo(x, n-1)
+	case 'm':
+		t.setGraphicRendition(params)
+	case 'r':
+		t.setScrollingRegion(param(params, 0, 1)-1,
+			param(params, 1, t.height)-1)
+	case 's':
+		t.saveCursor()
+	case 'u':
+		t.restoreCursor()
+	case 'h', 'l':
+		for _, mode := range params {
+			if mode != 4 {
+				t.unhandled++
+				continue
+			}
+
+			t.insert = final == 'h'
+		}
+	default:
+		t.unhandled++
+	}
+}
+
+// eraseDisplay implements ED (`CSI n J`).
+func (t *Terminal) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.erase(t.cursor.y, t.cursor.x, t.width)
+		t.eraseRows(t.cursor.y+1, t.height)
+	case 1:
+		t.eraseRows(0, t.cursor.y)
+		t.erase(t.cursor.y, 0, t.cursor.x+1)
+	case 2:
+		t.eraseRows(0, t.height)
+	}
+
+	t.wrapNext = false
+}
+
+// eraseLine implements EL (`CSI n K`).
+func (t *Terminal) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		t.erase(t.cursor.y, t.cursor.x, t.width)
+	case 1:
+		t.erase(t.cursor.y, 0, t.cursor.x+1)
+	case 2:
+		t.erase(t.cursor.y, 0, t.width)
+	}
+
+	t.wrapNext = false
+}
+
+// setScrollingRegion implements DECSTBM (`CSI top ; bottom r`).
+func (t *Terminal) setScrollingRegion(top, bottom int) {
+	top = clamp(top, 0, t.height-1)
+	bottom = clamp(bottom, 0, t.height-1)
+
+	if top >= bottom {
+		return
+	}
+
+	t.top, t.bottom = top, bottom
+	t.moveTo(0, 0)
+}
+
+// setPrivateMode implements DECSET (`CSI ? n h`) and DECRST
+// (`CSI ? n l`).
+func (t *Terminal) setPrivateMode(mode int, enabled bool) {
+	switch mode {
+	case 1, 12, 1000, 1002, 1003, 1004, 1005, 1006, 1015, 2004:
+		// cursor keys, blinking, mouse tracking and bracketed paste
+		// modes don't affect what gets displayed.
+	case 5:
+		t.reverse = enabled
+	case 7:
+		t.autowrap = enabled
+	case 25:
+		t.hidden = !enabled
+	case 47, 1047:
+		t.setAlternate(enabled)
+	case 1049:
+		if enabled {
+			t.saveCursor()
+			t.setAlternate(true)
+			return
+		}
+
+		t.setAlternate(false)
+		t.restoreCursor()
+	default:
+		t.unhandled++
+	}
+}
+
+// setGraphicRendition implements SGR (`CSI n ; ... m`).
+func (t *Terminal) setGraphicRendition(params []int) {
+	if len(params) == 0 {
+		t.pen = attributes{}
+		return
+	}
+
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+
+		switch {
+		case p <= 0:
+			t.pen = attributes{}
+		case p >= 1 && p <= 9:
+			t.pen.flags |= 1 << uint(p)
+		case p == 21:
+			t.pen.flags |= 1 << 4
+		case p == 22:
+			t.pen.flags &^= 1<<1 | 1<<2
+		case p >= 23 && p <= 29:
+			t.pen.flags &^= 1 << uint(p-20)
+		case p >= 30 && p <= 37, p >= 90 && p <= 97:
+			t.pen.foreground = strconv.Itoa(p)
+		case p == 39:
+			t.pen.foreground = ""
+		case p >= 40 && p <= 47, p >= 100 && p <= 107:
+			t.pen.background = strconv.Itoa(p)
+		case p == 49:
+			t.pen.background = ""
+		case p == 38, p == 48:
+			color, skip := extendedColor(params[i:])
+			i += skip
+
+			if p == 38 {
+				t.pen.foreground = color
+			} else {
+				t.pen.background = color
+			}
+		}
+	}
+}
+
+// extendedColor formats an extended color (`38;5;n` or `38;2;r;g;b`),
+// indicating how many parameters (besides the first) it took.
+func extendedColor(params []int) (color string, skip int) {
+	count := 0
+
+	switch param(params, 1, 0) {
+	case 5:
+		count = 3
+	case 2:
+		count = 5
+	default:
+		count = 1
+	}
+
+	if count > len(params) {
+		count = len(params)
+	}
+
+	values := make([]string, count)
+	for i := range values {
+		values[i] = strconv.Itoa(param(params, i, 0))
+	}
+
+	color = strings.Join(values, ";")
+	skip = count - 1
+	return
+}
+
+// parseParams parses the numeric parameters of a control sequence,
+// where missing ones are represented by -1 (see `param`).
+//
+// Sub-parameters (separated by `:`) are treated as parameters.
+func parseParams(body string) (params []int) {
+	if body == "" {
+		return
+	}
+
+	for _, field := range strings.Split(strings.Replace(body, ":", ";", -1), ";") {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			value = -1
+		}
+
+		params = append(params, value)
+	}
+
+	return
+}
+
+// param retrieves the parameter at position `idx`, falling back to
+// `def` if it's missing (or zero, when `def` is not zero).
+func param(params []int, idx, def int) int {
+	if idx >= len(params) || params[idx] < 0 {
+		return def
+	}
+
+	if params[idx] == 0 && def != 0 {
+		return def
+	}
+
+	return params[idx]
+}
+
+// clampUp moves `y` up by `n` rows, without crossing the top margin if
+// it starts below it.
+func clampUp(y, n, top int) int {
+	if y >= top && y-n < top {
+		return top
+	}
+
+	return y - n
+}
+
+// clampDown moves `y` down by `n` rows, without crossing the bottom
+// margin if it starts above it.
+func clampDown(y, n, bottom, height int) int {
+	if y <= bottom && y+n > bottom {
+		return bottom
+	}
+
+	return clamp(y+n, 0, height-1)
+}
@@ -0,0 +1,540 @@
+// Package terminal emulates the screen of a terminal, interpreting the
+// output that programs write to it.
+//
+// It implements the subset of the VT100/xterm control functions that
+// command line programs commonly rely on (cursor movement, erasing,
+// scrolling, text attributes, character sets and the alternate screen)
+// - enough to tell what a piece of output does to the screen, not to
+// render it pixel-perfectly (e.g., all characters take a single cell).
+//
+// Sequences that it doesn't interpret are counted (see `Unhandled`), so
+// that callers can tell when the emulated screen might not reflect what
+// a real terminal would display.
+package terminal
+
+import (
+	"strings"
+)
+
+// attributes are the graphic rendition attributes (SGR) of a cell.
+type attributes struct {
+	// foreground and background hold the SGR parameters that set the
+	// color (e.g., `31` or `38;5;208`), being empty for the default
+	// colors.
+	foreground string
+	background string
+
+	// flags has the bit `n` set for each enabled attribute that is
+	// turned on by the SGR parameter `n` (e.g., 1 for bold).
+	flags uint16
+}
+
+// cell is a single position of the screen.
+type cell struct {
+	r     rune
+	attrs attributes
+}
+
+// cursor is where the next character gets printed.
+type cursor struct {
+	x int
+	y int
+}
+
+// savedCursor is what gets saved by DECSC (`ESC 7`) and restored by
+// DECRC (`ESC 8`).
+type savedCursor struct {
+	cursor   cursor
+	pen      attributes
+	wrapNext bool
+}
+
+// Position identifies a cell of the screen (zero-based).
+type Position struct {
+	X int
+	Y int
+}
+
+// Terminal is a terminal screen that output can be written to.
+//
+// The zero value is not usable - terminals must be created with `New`.
+type Terminal struct {
+	width  int
+	height int
+
+	primary   [][]cell
+	alternate [][]cell
+	alt       bool
+
+	cursor   cursor
+	wrapNext bool
+	hidden   bool
+	pen      attributes
+	saved    savedCursor
+
+	top    int
+	bottom int
+
+	autowrap bool
+	insert   bool
+	reverse  bool
+
+	// charsets holds the character sets designated to G0 and G1 (the
+	// final byte of their designation, e.g., `B` for ASCII and `0`
+	// for DEC line drawing), while shifted indicates that G1 is the
+	// one in use (SO).
+	charsets [2]byte
+	shifted  bool
+
+	// unhandled is the number of sequences that were not interpreted.
+	unhandled int
+
+	// pending holds the end of the previous write that could not be
+	// interpreted yet (an unterminated escape sequence or an
+	// incomplete UTF-8 character).
+	pending string
+}
+
+// New creates a terminal with a blank screen of `width` columns and
+// `height` rows.
+func New(width, height int) (t *Terminal) {
+	t = &Terminal{}
+	t.reset(width, height)
+	return
+}
+
+// reset brings the terminal to its initial state.
+func (t *Terminal) reset(width, height int) {
+	*t = Terminal{
+		width:     width,
+		height:    height,
+		bottom:    height - 1,
+		autowrap:  true,
+		charsets:  [2]byte{'B', 'B'},
+		unhandled: t.unhandled,
+	}
+
+	t.primary = t.blankScreen()
+	t.alternate = t.blankScreen()
+}
+
+// Resize changes the size of the screen, keeping the contents that fit
+// in the new size.
+func (t *Terminal) Resize(width, height int) {
+	if width == t.width && height == t.height {
+		return
+	}
+
+	t.primary = resizeScreen(t.primary, width, height)
+	t.alternate = resizeScreen(t.alternate, width, height)
+	t.width, t.height = width, height
+	t.top, t.bottom = 0, height-1
+	t.wrapNext = false
+	t.cursor = t.clamp(t.cursor)
+	t.saved.cursor = t.clamp(t.saved.cursor)
+}
+
+// Clone creates an independent copy of the terminal.
+func (t *Terminal) Clone() (clone *Terminal) {
+	clone = &Terminal{}
+	*clone = *t
+	clone.primary = cloneScreen(t.primary)
+	clone.alternate = cloneScreen(t.alternate)
+	return
+}
+
+// Equal verifies whether two terminals are in the same state, i.e.,
+// whether writing the same output to both of them would lead to the
+// same screens.
+func (t *Terminal) Equal(other *Terminal) bool {
+	changes, ok := t.Changes(other)
+	return ok && len(changes) == 0
+}
+
+// Changes retrieves the positions of the cells of the screen that
+// differ between two terminals.
+//
+// `ok` is false if the terminals differ by anything but the contents
+// of the screen being displayed (e.g., the cursor position or the
+// size).
+//
+// The number of unhandled sequences is not taken into account.
+func (t *Terminal) Changes(other *Terminal) (changes []Position, ok bool) {
+	if t.width != other.width || t.height != other.height ||
+		t.alt != other.alt ||
+		t.cursor != other.cursor ||
+		t.wrapNext != other.wrapNext ||
+		t.hidden != other.hidden ||
+		t.pen != other.pen ||
+		t.saved != other.saved ||
+		t.top != other.top || t.bottom != other.bottom ||
+		t.autowrap != other.autowrap ||
+		t.insert != other.insert ||
+		t.reverse != other.reverse ||
+		t.charsets != other.charsets ||
+		t.shifted != other.shifted ||
+		t.pending != other.pending {
+		return
+	}
+
+	var (
+		screen, otherScreen     = t.primary, other.primary
+		inactive, otherInactive = t.alternate, other.alternate
+	)
+
+	if t.alt {
+		screen, otherScreen = t.alternate, other.alternate
+		inactive, otherInactive = t.primary, other.primary
+	}
+
+	for y := range inactive {
+		for x := range inactive[y] {
+			if inactive[y][x] != otherInactive[y][x] {
+				return
+			}
+		}
+	}
+
+	ok = true
+
+	for y := range screen {
+		for x := range screen[y] {
+			if screen[y][x] != otherScreen[y][x] {
+				changes = append(changes, Position{X: x, Y: y})
+			}
+		}
+	}
+
+	return
+}
+
+// Lines retrieves the text of each row of the screen being displayed,
+// without trailing spaces.
+func (t *Terminal) Lines() (lines []string) {
+	lines = make([]string, t.height)
+
+	for y, row := range t.screen() {
+		var builder strings.Builder
+
+		for _, c := range row {
+			builder.WriteRune(c.r)
+		}
+
+		lines[y] = strings.TrimRight(builder.String(), " ")
+	}
+
+	return
+}
+
+// Unhandled retrieves how many escape sequences and control characters
+// have not been interpreted since the terminal was created.
+func (t *Terminal) Unhandled() int {
+	return t.unhandled
+}
+
+// Cursor retrieves the position of the cursor.
+func (t *Terminal) Cursor() Position {
+	return Position{X: t.cursor.x, Y: t.cursor.y}
+}
+
+// screen retrieves the screen being displayed.
+func (t *Terminal) screen() [][]cell {
+	if t.alt {
+		return t.alternate
+	}
+
+	return t.primary
+}
+
+// blank is the cell left behind by erasing, which carries the
+// background color of the pen (just like xterm does).
+func (t *Terminal) blank() cell {
+	return cell{r: ' ', attrs: attributes{background: t.pen.background}}
+}
+
+func (t *Terminal) blankRow() (row []cell) {
+	row = make([]cell, t.width)
+	blank := t.blank()
+
+	for x := range row {
+		row[x] = blank
+	}
+
+	return
+}
+
+func (t *Terminal) blankScreen() (screen [][]cell) {
+	screen = make([][]cell, t.height)
+
+	for y := range screen {
+		screen[y] = t.blankRow()
+	}
+
+	return
+}
+
+// clamp brings a cursor position into the screen bounds.
+func (t *Terminal) clamp(c cursor) cursor {
+	c.x = clamp(c.x, 0, t.width-1)
+	c.y = clamp(c.y, 0, t.height-1)
+	return c
+}
+
+// moveTo moves the cursor to a position, clamping it to the screen.
+func (t *Terminal) moveTo(x, y int) {
+	t.cursor = t.clamp(cursor{x: x, y: y})
+	t.wrapNext = false
+}
+
+// print writes a character at the cursor position, wrapping to the
+// next line if the previous character filled the last column.
+func (t *Terminal) print(r rune) {
+	if t.wrapNext && t.autowrap {
+		t.cursor.x = 0
+		t.lineFeed()
+	}
+
+	t.wrapNext = false
+	row := t.screen()[t.cursor.y]
+	r = t.translate(r)
+
+	if t.insert {
+		copy(row[t.cursor.x+1:], row[t.cursor.x:])
+	}
+
+	row[t.cursor.x] = cell{r: r, attrs: t.pen}
+
+	if t.cursor.x == t.width-1 {
+		t.wrapNext = true
+		return
+	}
+
+	t.cursor.x++
+}
+
+// translate maps a character to what the character set in use displays
+// in its place.
+func (t *Terminal) translate(r rune) rune {
+	charset := t.charsets[0]
+	if t.shifted {
+		charset = t.charsets[1]
+	}
+
+	if charset != '0' || r < 0x5f || r > 0x7e {
+		return r
+	}
+
+	return lineDrawing[r-0x5f]
+}
+
+// lineDrawing is the DEC Special Graphics character set, which replaces
+// the characters from `_` to `~`.
+var lineDrawing = []rune(" ◆▒␉␌␍␊°±␤␋┘┐┌└┼⎺⎻─⎼⎽├┤┴┬│≤≥π≠£·")
+
+// lineFeed moves the cursor down, scrolling the scrolling region up if
+// the cursor is at its bottom.
+func (t *Terminal) lineFeed() {
+	t.wrapNext = false
+
+	if t.cursor.y == t.bottom {
+		t.scrollUp(1)
+		return
+	}
+
+	if t.cursor.y < t.height-1 {
+		t.cursor.y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling the scrolling region
+// down if the cursor is at its top.
+func (t *Terminal) reverseIndex() {
+	t.wrapNext = false
+
+	if t.cursor.y == t.top {
+		t.scrollDown(1)
+		return
+	}
+
+	if t.cursor.y > 0 {
+		t.cursor.y--
+	}
+}
+
+// tab moves the cursor to the next tab stop (every 8 columns).
+func (t *Terminal) tab() {
+	t.cursor.x = clamp((t.cursor.x/8+1)*8, 0, t.width-1)
+	t.wrapNext = false
+}
+
+// scrollUp moves the rows of the scrolling region `n` rows up,
+// inserting blank rows at its bottom.
+func (t *Terminal) scrollUp(n int) {
+	t.deleteRows(t.top, n)
+}
+
+// scrollDown moves the rows of the scrolling region `n` rows down,
+// inserting blank rows at its top.
+func (t *Terminal) scrollDown(n int) {
+	t.insertRows(t.top, n)
+}
+
+// insertRows inserts `n` blank rows at row `y`, pushing the rows below
+// it (up to the bottom of the scrolling region) down.
+func (t *Terminal) insertRows(y, n int) {
+	if y < t.top || y > t.bottom {
+		return
+	}
+
+	var (
+		screen = t.screen()
+		region = screen[y : t.bottom+1]
+	)
+
+	n = clamp(n, 0, len(region))
+	copy(region[n:], region[:len(region)-n])
+
+	for i := 0; i < n; i++ {
+		region[i] = t.blankRow()
+	}
+}
+
+// deleteRows removes `n` rows at row `y`, pulling the rows below it
+// (up to the bottom of the scrolling region) up.
+func (t *Terminal) deleteRows(y, n int) {
+	if y < t.top || y > t.bottom {
+		return
+	}
+
+	var (
+		screen = t.screen()
+		region = screen[y : t.bottom+1]
+	)
+
+	n = clamp(n, 0, len(region))
+	copy(region, region[n:])
+
+	for i := len(region) - n; i < len(region); i++ {
+		region[i] = t.blankRow()
+	}
+}
+
+// erase blanks the cells of a row from column `from` to column `to`
+// (`to` not included).
+func (t *Terminal) erase(y, from, to int) {
+	var (
+		row   = t.screen()[y]
+		blank = t.blank()
+	)
+
+	for x := clamp(from, 0, t.width); x < clamp(to, 0, t.width); x++ {
+		row[x] = blank
+	}
+}
+
+// eraseRows blanks the rows from `from` to `to` (`to` not included).
+func (t *Terminal) eraseRows(from, to int) {
+	for y := clamp(from, 0, t.height); y < clamp(to, 0, t.height); y++ {
+		t.erase(y, 0, t.width)
+	}
+}
+
+// insertCells inserts `n` blank cells at the cursor position, pushing
+// the rest of the row to the right.
+func (t *Terminal) insertCells(n int) {
+	var (
+		row = t.screen()[t.cursor.y][t.cursor.x:]
+		x   int
+	)
+
+	n = clamp(n, 0, len(row))
+	copy(row[n:], row[:len(row)-n])
+
+	for x = 0; x < n; x++ {
+		row[x] = t.blank()
+	}
+}
+
+// deleteCells removes `n` cells at the cursor position, pulling the
+// rest of the row to the left.
+func (t *Terminal) deleteCells(n int) {
+	var (
+		row = t.screen()[t.cursor.y][t.cursor.x:]
+		x   int
+	)
+
+	n = clamp(n, 0, len(row))
+	copy(row, row[n:])
+
+	for x = len(row) - n; x < len(row); x++ {
+		row[x] = t.blank()
+	}
+}
+
+// setAlternate switches between the primary and the alternate screen.
+func (t *Terminal) setAlternate(alt bool) {
+	if t.alt == alt {
+		return
+	}
+
+	t.alt = alt
+
+	if alt {
+		t.alternate = t.blankScreen()
+	}
+}
+
+func (t *Terminal) saveCursor() {
+	t.saved = savedCursor{
+		cursor:   t.cursor,
+		pen:      t.pen,
+		wrapNext: t.wrapNext,
+	}
+}
+
+func (t *Terminal) restoreCursor() {
+	t.cursor = t.clamp(t.saved.cursor)
+	t.pen = t.saved.pen
+	t.wrapNext = t.saved.wrapNext
+}
+
+func resizeScreen(screen [][]cell, width, height int) (resized [][]cell) {
+	resized = make([][]cell, height)
+
+	for y := range resized {
+		resized[y] = make([]cell, width)
+
+		for x := range resized[y] {
+			resized[y][x] = cell{r: ' '}
+		}
+
+		if y < len(screen) {
+			copy(resized[y], screen[y])
+		}
+	}
+
+	return
+}
+
+func cloneScreen(screen [][]cell) (clone [][]cell) {
+	clone = make([][]cell, len(screen))
+
+	for y := range screen {
+		clone[y] = make([]cell, len(screen[y]))
+		copy(clone[y], screen[y])
+	}
+
+	return
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+
+	if value > max {
+		return max
+	}
+
+	return value
+}
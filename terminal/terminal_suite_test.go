@@ -0,0 +1,13 @@
+package terminal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTerminal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Terminal Suite")
+}
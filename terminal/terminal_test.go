@@ -0,0 +1,139 @@
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cirocosta/asciinema-edit/terminal"
+)
+
+var _ = Describe("Terminal", func() {
+	var term *terminal.Terminal
+
+	BeforeEach(func() {
+		term = terminal.New(10, 3)
+	})
+
+	Describe("Write", func() {
+		It("prints text and moves the cursor", func() {
+			term.Write("ab\r\ncd")
+			Expect(term.Lines()).To(Equal([]string{"ab", "cd", ""}))
+			Expect(term.Cursor()).To(Equal(terminal.Position{X: 2, Y: 1}))
+		})
+
+		It("wraps long lines", func() {
+			term.Write("0123456789ab")
+			Expect(term.Lines()).To(Equal([]string{"0123456789", "ab", ""}))
+		})
+
+		It("scrolls when reaching the bottom", func() {
+			term.Write("a\r\nb\r\nc\r\nd")
+			Expect(term.Lines()).To(Equal([]string{"b", "c", "d"}))
+		})
+
+		It("moves the cursor and erases", func() {
+			term.Write("hello\r\nworld")
+			term.Write("\x1b[1;2H\x1b[K")
+			Expect(term.Lines()).To(Equal([]string{"h", "world", ""}))
+
+			term.Write("\x1b[2J")
+			Expect(term.Lines()).To(Equal([]string{"", "", ""}))
+		})
+
+		It("switches to the alternate screen and back", func() {
+			term.Write("shell")
+			term.Write("\x1b[?1049h\x1b[Hvim")
+			Expect(term.Lines()).To(Equal([]string{"vim", "", ""}))
+
+			term.Write("\x1b[?1049l")
+			Expect(term.Lines()).To(Equal([]string{"shell", "", ""}))
+			Expect(term.Cursor()).To(Equal(terminal.Position{X: 5, Y: 0}))
+		})
+
+		It("draws lines with the DEC line drawing character set", func() {
+			term.Write("\x1b(0lqqk\x1b(B lqqk")
+			Expect(term.Lines()).To(Equal([]string{"┌──┐ lqqk", "", ""}))
+
+			term.Write("\r\n\x1b)0\x0elqk\x0flqk")
+			Expect(term.Lines()).To(Equal([]string{
+				"┌──┐ lqqk", "┌─┐lqk", "",
+			}))
+		})
+
+		It("counts the sequences it doesn't interpret", func() {
+			term.Write("\x1b]2;title\x07\x1b[?2004h\x1b[1m")
+			Expect(term.Unhandled()).To(Equal(0))
+
+			term.Write("\x1b[5 q\x1b]4;1;red\x07")
+			Expect(term.Unhandled()).To(Equal(2))
+		})
+
+		It("waits for the rest of split sequences and characters", func() {
+			term.Write("\x1b[")
+			term.Write("2Ca\xc3")
+			term.Write("\xa7")
+			Expect(term.Lines()).To(Equal([]string{"  aç", "", ""}))
+		})
+	})
+
+	Describe("Equal", func() {
+		It("holds for output that doesn't change anything", func() {
+			term.Write("\r50%")
+			before := term.Clone()
+
+			term.Write("\r50%")
+			Expect(term.Equal(before)).To(BeTrue())
+		})
+
+		It("takes the text attributes into account", func() {
+			term.Write("\r50%")
+			before := term.Clone()
+
+			term.Write("\r\x1b[1m50%\x1b[0m")
+			Expect(term.Equal(before)).To(BeFalse())
+		})
+
+		It("takes the character set into account", func() {
+			before := term.Clone()
+
+			term.Write("\x1b(0")
+			Expect(term.Equal(before)).To(BeFalse())
+		})
+
+		It("takes the pen into account", func() {
+			before := term.Clone()
+
+			term.Write("\x1b[31m")
+			Expect(term.Equal(before)).To(BeFalse())
+		})
+	})
+
+	Describe("Changes", func() {
+		It("lists the cells that changed", func() {
+			term.Write("| loading")
+			before := term.Clone()
+
+			term.Write("\r/\x1b[9C")
+			changes, ok := term.Changes(before)
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(Equal([]terminal.Position{{X: 0, Y: 0}}))
+		})
+
+		It("is not ok when the cursor moves", func() {
+			before := term.Clone()
+
+			term.Write("a")
+			_, ok := term.Changes(before)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Resize", func() {
+		It("keeps the contents that fit", func() {
+			term.Write("0123456789\r\nab")
+			term.Resize(4, 2)
+			Expect(term.Lines()).To(Equal([]string{"0123", "ab"}))
+			Expect(term.Cursor()).To(Equal(terminal.Position{X: 2, Y: 1}))
+		})
+	})
+})